@@ -0,0 +1,75 @@
+// Команда jwtkeys создаёт ключ подписи JWT и его открытую часть:
+//
+//	go run ./cmd/jwtkeys -out keys/jwt-2026-10.pem
+//
+// Закрытый ключ указывается в JWT_SIGNING_KEY_FILE. При ротации файл прежнего ключа
+// (достаточно .pub.pem) переносится в JWT_VERIFICATION_KEY_FILES, пока не истекут выданные им токены.
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	alg := flag.String("alg", "EdDSA", "signing algorithm: EdDSA or RS256")
+	out := flag.String("out", "", "private key file; the public key is written next to it with the .pub.pem suffix")
+	flag.Parse()
+
+	if *out == "" {
+		log.Fatal("-out is required")
+	}
+
+	var key crypto.Signer
+	var err error
+	switch *alg {
+	case "EdDSA":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	case "RS256":
+		key, err = rsa.GenerateKey(rand.Reader, 3072)
+	default:
+		log.Fatalf("Unsupported algorithm %q", *alg)
+	}
+	if err != nil {
+		log.Fatal("Error generating key:", err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		log.Fatal("Error encoding private key:", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		log.Fatal("Error encoding public key:", err)
+	}
+
+	publicFile := strings.TrimSuffix(*out, ".pem") + ".pub.pem"
+	if err := writePEM(*out, "PRIVATE KEY", privateDER, 0600); err != nil {
+		log.Fatal("Error writing private key:", err)
+	}
+	if err := writePEM(publicFile, "PUBLIC KEY", publicDER, 0644); err != nil {
+		log.Fatal("Error writing public key:", err)
+	}
+	log.Printf("Wrote %s and %s\n", *out, publicFile)
+}
+
+func writePEM(path, blockType string, der []byte, perm os.FileMode) error {
+	// O_EXCL: случайно перезаписанный ключ подписи разлогинит всех пользователей
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if err := pem.Encode(f, &pem.Block{Type: blockType, Bytes: der}); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -3,10 +3,24 @@ package main
 import (
 	"birthdayReminder/internal/handler"
 	"birthdayReminder/internal/handler/auth"
+	"birthdayReminder/internal/netguard"
 	"birthdayReminder/internal/notifier"
+	"birthdayReminder/internal/repository/announcement"
+	"birthdayReminder/internal/repository/inbox"
+	"birthdayReminder/internal/repository/lock"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/push"
+	"birthdayReminder/internal/repository/refresh"
+	"birthdayReminder/internal/repository/revocation"
 	"birthdayReminder/internal/repository/subscription"
+	telegramRepo "birthdayReminder/internal/repository/telegram"
 	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/repository/webhook"
+	"birthdayReminder/internal/stream"
+	"birthdayReminder/internal/telegram"
+	"birthdayReminder/internal/webpush"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -14,6 +28,11 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// встроенная база часовых поясов, чтобы не зависеть от tzdata в образе
+	_ "time/tzdata"
 )
 
 func main() {
@@ -36,16 +55,92 @@ func main() {
 
 	userRepo := user.NewRepo(pool)
 	subscriptionRepo := subscription.NewRepo(pool)
-	tokenManager := &auth.TokenService{}
+	notificationRepo := notification.NewRepo(pool)
+	lockRepo := lock.NewRepo(pool)
+	linkRepo := telegramRepo.NewRepo(pool)
+	webhookRepo := webhook.NewRepo(pool)
+	announcementRepo := announcement.NewRepo(pool)
+	inboxRepo := inbox.NewRepo(pool)
+	pushRepo := push.NewRepo(pool)
+	refreshRepo := refresh.NewRepo(pool)
+	jwtKeys, err := auth.KeySetFromEnv()
+	if err != nil {
+		log.Fatal("Error loading JWT keys:", err)
+	}
+	// отзыв токена на другом экземпляре сервиса начинает действовать здесь не позже чем через 30 секунд
+	tokenManager := &auth.TokenService{Keys: jwtKeys, Denylist: auth.NewDenylist(revocation.NewRepo(pool), 30*time.Second)}
+	// события потока расходятся между экземплярами через Postgres LISTEN/NOTIFY
+	hub := stream.NewHub(stream.NewPGBroker(pool))
+
+	mailConfig, err := notifier.MailConfigFromEnv()
+	if err != nil {
+		log.Fatal("Error loading mail config:", err)
+	}
+
+	emailSender, err := notifier.NewEmailSenderFromConfig(mailConfig)
+	if err != nil {
+		log.Fatal("Error creating email sender:", err)
+	}
+
+	senders := notifier.NewRegistry(emailSender, notifier.NewWebhookSender(webhookRepo, netguard.AllowPrivateFromEnv()), notifier.NewInAppSender(inboxRepo, hub))
+
+	if privateKey := os.Getenv("VAPID_PRIVATE_KEY"); privateKey != "" {
+		vapid, err := webpush.NewVAPID(os.Getenv("VAPID_PUBLIC_KEY"), privateKey, os.Getenv("VAPID_SUBJECT"))
+		if err != nil {
+			log.Fatal("Error loading VAPID keys:", err)
+		}
+		senders.Register(notifier.NewPushSender(pushRepo, webpush.NewClient(vapid, netguard.AllowPrivateFromEnv())))
+	}
+
+	var telegramBot *telegram.Bot
+	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+		telegramClient := telegram.NewClient(os.Getenv("TELEGRAM_API_URL"), token)
+		senders.Register(notifier.NewTelegramSender(telegramClient))
+		// при нескольких экземплярах сервиса опрашивать Bot API должен только один
+		if os.Getenv("TELEGRAM_POLLING") != "false" {
+			telegramBot = telegram.NewBot(telegramClient, linkRepo)
+		}
+	}
+
+	notifierConfig, err := notifier.ConfigFromEnv()
+	if err != nil {
+		log.Fatal("Error loading notifier config:", err)
+	}
 
-	notify := notifier.New(userRepo, subscriptionRepo)
-	//notify.StartBirthdayNotifier()
-	notify.SendBirthdayNotifications()
+	notify := notifier.New(userRepo, subscriptionRepo, notificationRepo, announcementRepo, senders, lockRepo, notifierConfig)
+	if err := notify.StartBirthdayNotifier(); err != nil {
+		log.Fatal("Error starting birthday notifier:", err)
+	}
 
 	router := mux.NewRouter()
-	handler.InitRoutes(router, userRepo, subscriptionRepo, tokenManager)
+	handler.InitRoutes(router, userRepo, subscriptionRepo, notificationRepo, linkRepo, webhookRepo, announcementRepo, inboxRepo, pushRepo, refreshRepo, emailSender, hub, tokenManager)
 
 	port := ":8080"
-	fmt.Println("Server is running on", port)
-	log.Fatal(http.ListenAndServe(port, router))
+	server := &http.Server{Addr: port, Handler: router}
+	server.RegisterOnShutdown(hub.Close)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go hub.Run(ctx)
+	if telegramBot != nil {
+		go telegramBot.Run(ctx)
+	}
+
+	go func() {
+		fmt.Println("Server is running on", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Error shutting down server:", err)
+	}
+	notify.Stop()
 }
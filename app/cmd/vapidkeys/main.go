@@ -0,0 +1,18 @@
+// Команда vapidkeys создаёт пару ключей VAPID для Web Push:
+//
+//	go run ./cmd/vapidkeys >> .env
+package main
+
+import (
+	"birthdayReminder/internal/webpush"
+	"fmt"
+	"log"
+)
+
+func main() {
+	publicKey, privateKey, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		log.Fatal("Error generating VAPID keys:", err)
+	}
+	fmt.Printf("VAPID_PUBLIC_KEY=%s\nVAPID_PRIVATE_KEY=%s\n", publicKey, privateKey)
+}
@@ -0,0 +1,14 @@
+-- журнал отправленных напоминаний
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    subscriber_id INT NOT NULL REFERENCES users(id),
+    celebrant_id INT NOT NULL REFERENCES users(id),
+    birthday_year INT NOT NULL,
+    channel VARCHAR(32) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (subscriber_id, celebrant_id, birthday_year, channel)
+);
@@ -0,0 +1,7 @@
+-- повторные отправки и администраторы
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS payload JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS notifications_retry_idx ON notifications (next_attempt_at) WHERE status = 'failed';
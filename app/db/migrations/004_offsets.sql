@@ -0,0 +1,30 @@
+-- несколько сроков напоминания на подписку
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS offsets INT[] NOT NULL DEFAULT '{1}';
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS offset_days INT NOT NULL DEFAULT 1;
+
+-- уникальность с учетом срока; после 011 ключом служит dedupe_key, и этот шаг пропускается
+DO $$
+DECLARE
+    c RECORD;
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'notifications' AND column_name = 'dedupe_key') THEN
+        RETURN;
+    END IF;
+
+    FOR c IN
+        SELECT con.conname FROM pg_constraint con
+        WHERE con.conrelid = 'notifications'::regclass AND con.contype = 'u'
+          AND NOT EXISTS (
+              SELECT 1 FROM pg_attribute a
+              WHERE a.attrelid = con.conrelid AND a.attnum = ANY (con.conkey) AND a.attname = 'offset_days'
+          )
+    LOOP
+        EXECUTE format('ALTER TABLE notifications DROP CONSTRAINT %I', c.conname);
+    END LOOP;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'notifications_offset_key') THEN
+        ALTER TABLE notifications ADD CONSTRAINT notifications_offset_key
+            UNIQUE (subscriber_id, celebrant_id, birthday_year, offset_days, channel);
+    END IF;
+END $$;
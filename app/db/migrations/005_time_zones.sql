@@ -0,0 +1,3 @@
+-- часовой пояс и час отправки подписчика
+ALTER TABLE users ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS notify_hour SMALLINT NOT NULL DEFAULT 12 CHECK (notify_hour BETWEEN 0 AND 23);
@@ -0,0 +1,2 @@
+-- день празднования для родившихся 29 февраля
+ALTER TABLE users ADD COLUMN IF NOT EXISTS leap_policy VARCHAR(8) NOT NULL DEFAULT 'feb28';
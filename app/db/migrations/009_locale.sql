@@ -0,0 +1,2 @@
+-- язык уведомлений
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(8) NOT NULL DEFAULT 'ru';
@@ -0,0 +1,37 @@
+-- дайджесты: у записи журнала может не быть именинника, дубликаты определяются по dedupe_key
+ALTER TABLE users ADD COLUMN IF NOT EXISTS delivery_mode VARCHAR(16) NOT NULL DEFAULT 'individual';
+
+ALTER TABLE notifications ALTER COLUMN celebrant_id DROP NOT NULL;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS kind VARCHAR(16) NOT NULL DEFAULT 'reminder';
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS dedupe_key VARCHAR(128);
+
+-- ключ в том же формате, что и у новых напоминаний: reminder:<именинник>:<год>:<срок>
+UPDATE notifications
+SET dedupe_key = 'reminder:' || celebrant_id || ':' || birthday_year || ':' || offset_days
+WHERE dedupe_key IS NULL;
+
+ALTER TABLE notifications ALTER COLUMN dedupe_key SET NOT NULL;
+
+DO $$
+DECLARE
+    c RECORD;
+BEGIN
+    FOR c IN
+        SELECT con.conname FROM pg_constraint con
+        WHERE con.conrelid = 'notifications'::regclass AND con.contype = 'u'
+          AND NOT EXISTS (
+              SELECT 1 FROM pg_attribute a
+              WHERE a.attrelid = con.conrelid AND a.attnum = ANY (con.conkey) AND a.attname = 'dedupe_key'
+          )
+    LOOP
+        EXECUTE format('ALTER TABLE notifications DROP CONSTRAINT %I', c.conname);
+    END LOOP;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conrelid = 'notifications'::regclass AND conname = 'notifications_subscriber_id_dedupe_key_channel_key'
+    ) THEN
+        ALTER TABLE notifications ADD CONSTRAINT notifications_subscriber_id_dedupe_key_channel_key
+            UNIQUE (subscriber_id, dedupe_key, channel);
+    END IF;
+END $$;
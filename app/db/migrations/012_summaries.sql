@@ -0,0 +1,3 @@
+-- недельные и месячные сводки
+ALTER TABLE users ADD COLUMN IF NOT EXISTS weekly_summary BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS monthly_summary BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,8 @@
+-- привязка Telegram
+ALTER TABLE users ADD COLUMN IF NOT EXISTS telegram_chat_id BIGINT;
+
+CREATE TABLE IF NOT EXISTS telegram_link_codes (
+    code VARCHAR(16) PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    expires_at TIMESTAMPTZ NOT NULL
+);
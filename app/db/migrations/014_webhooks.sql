@@ -0,0 +1,23 @@
+-- исходящие вебхуки и журнал их доставки
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    url TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    is_global BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    webhook_id INT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id VARCHAR(160) NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    status_code INT,
+    error TEXT,
+    success BOOLEAN NOT NULL,
+    duration_ms INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, created_at DESC);
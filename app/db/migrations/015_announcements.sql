@@ -0,0 +1,20 @@
+-- объявления в командных чатах
+CREATE TABLE IF NOT EXISTS announcement_channels (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    format TEXT NOT NULL,
+    days_ahead SMALLINT NOT NULL DEFAULT 1,
+    time_zone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow',
+    post_hour SMALLINT NOT NULL DEFAULT 10 CHECK (post_hour BETWEEN 0 AND 23),
+    created_by INT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS announcement_log (
+    channel_id INT NOT NULL REFERENCES announcement_channels(id) ON DELETE CASCADE,
+    celebrant_id INT NOT NULL REFERENCES users(id),
+    birthday DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (channel_id, celebrant_id, birthday)
+);
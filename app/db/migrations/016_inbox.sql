@@ -0,0 +1,15 @@
+-- центр уведомлений
+CREATE TABLE IF NOT EXISTS inbox (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    celebrant_id INT REFERENCES users(id) ON DELETE SET NULL,
+    event_id VARCHAR(160) NOT NULL,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS inbox_user_idx ON inbox (user_id, created_at DESC);
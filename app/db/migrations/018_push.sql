@@ -0,0 +1,20 @@
+-- подписки Web Push
+CREATE TABLE IF NOT EXISTS push_subscriptions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    endpoint TEXT NOT NULL UNIQUE,
+    p256dh TEXT NOT NULL,
+    auth TEXT NOT NULL,
+    device VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS push_subscriptions_user_idx ON push_subscriptions (user_id);
+
+-- доставленные на устройство push-уведомления: повторная отправка пропускает эти устройства
+CREATE TABLE IF NOT EXISTS push_deliveries (
+    subscription_id INT NOT NULL REFERENCES push_subscriptions(id) ON DELETE CASCADE,
+    event_id VARCHAR(160) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, event_id)
+);
@@ -0,0 +1,4 @@
+-- настройки уведомлений
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_channels TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS quiet_start SMALLINT NOT NULL DEFAULT 0 CHECK (quiet_start BETWEEN 0 AND 23);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS quiet_end SMALLINT NOT NULL DEFAULT 0 CHECK (quiet_end BETWEEN 0 AND 23);
@@ -0,0 +1,2 @@
+-- пауза в напоминаниях по подписке
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS snoozed_until DATE;
@@ -0,0 +1,12 @@
+-- отозванные до истечения срока JWT (выход из сессии)
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(32) PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- выход на всех устройствах
+CREATE TABLE IF NOT EXISTS user_token_revocations (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMPTZ NOT NULL
+);
@@ -3,11 +3,161 @@ CREATE TABLE users (
     name VARCHAR(255) NOT NULL,
     email VARCHAR(255) UNIQUE NOT NULL,
     password VARCHAR(255) NOT NULL,
-    date_of_birth DATE NOT NULL
+    date_of_birth DATE NOT NULL,
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    time_zone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow',
+    notify_hour SMALLINT NOT NULL DEFAULT 12 CHECK (notify_hour BETWEEN 0 AND 23),
+    leap_policy VARCHAR(8) NOT NULL DEFAULT 'feb28',
+    locale VARCHAR(8) NOT NULL DEFAULT 'ru',
+    delivery_mode VARCHAR(16) NOT NULL DEFAULT 'individual',
+    weekly_summary BOOLEAN NOT NULL DEFAULT FALSE,
+    monthly_summary BOOLEAN NOT NULL DEFAULT FALSE,
+    telegram_chat_id BIGINT,
+    disabled_channels TEXT[] NOT NULL DEFAULT '{}',
+    quiet_start SMALLINT NOT NULL DEFAULT 0 CHECK (quiet_start BETWEEN 0 AND 23),
+    quiet_end SMALLINT NOT NULL DEFAULT 0 CHECK (quiet_end BETWEEN 0 AND 23),
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    -- jti последней отправленной ссылки подтверждения: действует только она, и только один раз
+    verification_token_id VARCHAR(64),
+    verification_sent_at TIMESTAMPTZ
 );
 
 CREATE TABLE subscriptions (
     id SERIAL PRIMARY KEY,
     user_id INT NOT NULL REFERENCES users(id),
-    related_user_id INT NOT NULL REFERENCES users(id)
-);
\ No newline at end of file
+    related_user_id INT NOT NULL REFERENCES users(id),
+    offsets INT[] NOT NULL DEFAULT '{1}',
+    snoozed_until DATE
+);
+
+CREATE TABLE telegram_link_codes (
+    code VARCHAR(16) PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE webhooks (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    url TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    is_global BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    webhook_id INT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id VARCHAR(160) NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    status_code INT,
+    error TEXT,
+    success BOOLEAN NOT NULL,
+    duration_ms INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, created_at DESC);
+
+CREATE TABLE announcement_channels (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    format TEXT NOT NULL,
+    days_ahead SMALLINT NOT NULL DEFAULT 1,
+    time_zone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow',
+    post_hour SMALLINT NOT NULL DEFAULT 10 CHECK (post_hour BETWEEN 0 AND 23),
+    created_by INT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE announcement_log (
+    channel_id INT NOT NULL REFERENCES announcement_channels(id) ON DELETE CASCADE,
+    celebrant_id INT NOT NULL REFERENCES users(id),
+    birthday DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (channel_id, celebrant_id, birthday)
+);
+
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    subscriber_id INT NOT NULL REFERENCES users(id),
+    celebrant_id INT REFERENCES users(id),
+    birthday_year INT NOT NULL,
+    offset_days INT NOT NULL DEFAULT 1,
+    channel VARCHAR(32) NOT NULL,
+    kind VARCHAR(16) NOT NULL DEFAULT 'reminder',
+    dedupe_key VARCHAR(128) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (subscriber_id, dedupe_key, channel)
+);
+
+CREATE INDEX notifications_retry_idx ON notifications (next_attempt_at) WHERE status = 'failed';
+
+CREATE TABLE inbox (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    celebrant_id INT REFERENCES users(id) ON DELETE SET NULL,
+    event_id VARCHAR(160) NOT NULL,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, event_id)
+);
+
+CREATE INDEX inbox_user_idx ON inbox (user_id, created_at DESC);
+
+CREATE TABLE push_subscriptions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    endpoint TEXT NOT NULL UNIQUE,
+    p256dh TEXT NOT NULL,
+    auth TEXT NOT NULL,
+    device VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX push_subscriptions_user_idx ON push_subscriptions (user_id);
+
+-- доставленные на устройство push-уведомления: повторная отправка пропускает эти устройства
+CREATE TABLE push_deliveries (
+    subscription_id INT NOT NULL REFERENCES push_subscriptions(id) ON DELETE CASCADE,
+    event_id VARCHAR(160) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, event_id)
+);
+
+-- refresh-токены хранятся только в виде SHA-256; токены одной цепочки ротаций делят family_id
+CREATE TABLE refresh_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id VARCHAR(32) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX refresh_tokens_family_idx ON refresh_tokens (family_id);
+
+-- отозванные до истечения срока JWT (выход из сессии)
+CREATE TABLE revoked_tokens (
+    jti VARCHAR(32) PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- выход на всех устройствах: недействительны все JWT пользователя, выданные не позже revoked_before
+CREATE TABLE user_token_revocations (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMPTZ NOT NULL
+);
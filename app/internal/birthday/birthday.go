@@ -0,0 +1,56 @@
+package birthday
+
+import "time"
+
+// Policy определяет, когда празднуется 29 февраля в невисокосный год.
+type Policy string
+
+const (
+	PolicyFeb28 Policy = "feb28"
+	PolicyMar1  Policy = "mar1"
+
+	DefaultPolicy = PolicyFeb28
+)
+
+func (p Policy) Valid() bool {
+	return p == PolicyFeb28 || p == PolicyMar1
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// Occurrence возвращает дату празднования дня рождения в году year в часовом поясе loc.
+func Occurrence(dateOfBirth time.Time, year int, policy Policy, loc *time.Location) time.Time {
+	month, day := dateOfBirth.Month(), dateOfBirth.Day()
+	if month == time.February && day == 29 && !isLeap(year) {
+		if policy == PolicyMar1 {
+			month, day = time.March, 1
+		} else {
+			day = 28
+		}
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// IsOn сообщает, празднуется ли день рождения в день day (учитывается только дата).
+func IsOn(dateOfBirth time.Time, day time.Time, policy Policy) bool {
+	occurrence := Occurrence(dateOfBirth, day.Year(), policy, day.Location())
+	return occurrence.Month() == day.Month() && occurrence.Day() == day.Day()
+}
+
+// Next возвращает ближайшее празднование не раньше дня from.
+func Next(dateOfBirth time.Time, from time.Time, policy Policy) time.Time {
+	today := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+
+	occurrence := Occurrence(dateOfBirth, from.Year(), policy, from.Location())
+	if occurrence.Before(today) {
+		occurrence = Occurrence(dateOfBirth, from.Year()+1, policy, from.Location())
+	}
+	return occurrence
+}
+
+// Age возвращает возраст, который исполняется в празднование occurrence.
+func Age(dateOfBirth time.Time, occurrence time.Time) int {
+	return occurrence.Year() - dateOfBirth.Year()
+}
@@ -0,0 +1,93 @@
+package birthday
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestOccurrence(t *testing.T) {
+	leapling := date(2000, time.February, 29)
+
+	testCases := []struct {
+		name        string
+		dateOfBirth time.Time
+		year        int
+		policy      Policy
+		expected    time.Time
+	}{
+		{name: "Regular birthday", dateOfBirth: date(1990, time.October, 18), year: 2026, policy: PolicyFeb28, expected: date(2026, time.October, 18)},
+		{name: "Feb 29 in leap year", dateOfBirth: leapling, year: 2028, policy: PolicyFeb28, expected: date(2028, time.February, 29)},
+		{name: "Feb 29 in leap year with mar1 policy", dateOfBirth: leapling, year: 2028, policy: PolicyMar1, expected: date(2028, time.February, 29)},
+		{name: "Feb 29 in non-leap year, feb28 policy", dateOfBirth: leapling, year: 2026, policy: PolicyFeb28, expected: date(2026, time.February, 28)},
+		{name: "Feb 29 in non-leap year, mar1 policy", dateOfBirth: leapling, year: 2026, policy: PolicyMar1, expected: date(2026, time.March, 1)},
+		{name: "Feb 29 in century non-leap year", dateOfBirth: leapling, year: 2100, policy: PolicyMar1, expected: date(2100, time.March, 1)},
+		{name: "Feb 29 in 400-year leap year", dateOfBirth: leapling, year: 2400, policy: PolicyMar1, expected: date(2400, time.February, 29)},
+		{name: "Unknown policy falls back to feb28", dateOfBirth: leapling, year: 2027, policy: "", expected: date(2027, time.February, 28)},
+		{name: "Feb 28 birthday is not moved", dateOfBirth: date(1999, time.February, 28), year: 2028, policy: PolicyMar1, expected: date(2028, time.February, 28)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Occurrence(tt.dateOfBirth, tt.year, tt.policy, time.UTC))
+		})
+	}
+}
+
+func TestIsOn(t *testing.T) {
+	leapling := date(2000, time.February, 29)
+
+	testCases := []struct {
+		name     string
+		day      time.Time
+		policy   Policy
+		expected bool
+	}{
+		{name: "Feb 28 of non-leap year, feb28 policy", day: date(2026, time.February, 28), policy: PolicyFeb28, expected: true},
+		{name: "Mar 1 of non-leap year, feb28 policy", day: date(2026, time.March, 1), policy: PolicyFeb28, expected: false},
+		{name: "Mar 1 of non-leap year, mar1 policy", day: date(2026, time.March, 1), policy: PolicyMar1, expected: true},
+		{name: "Feb 28 of non-leap year, mar1 policy", day: date(2026, time.February, 28), policy: PolicyMar1, expected: false},
+		{name: "Feb 28 of leap year", day: date(2028, time.February, 28), policy: PolicyFeb28, expected: false},
+		{name: "Feb 29 of leap year", day: date(2028, time.February, 29), policy: PolicyFeb28, expected: true},
+		{name: "Mar 1 of leap year", day: date(2028, time.March, 1), policy: PolicyMar1, expected: false},
+		{name: "Day in another time zone", day: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.FixedZone("UTC+14", 14*3600)), policy: PolicyMar1, expected: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsOn(leapling, tt.day, tt.policy))
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	testCases := []struct {
+		name        string
+		dateOfBirth time.Time
+		from        time.Time
+		policy      Policy
+		expected    time.Time
+	}{
+		{name: "Later this year", dateOfBirth: date(1990, time.October, 18), from: time.Date(2026, time.October, 17, 15, 0, 0, 0, time.UTC), expected: date(2026, time.October, 18)},
+		{name: "Today counts", dateOfBirth: date(1990, time.October, 17), from: time.Date(2026, time.October, 17, 23, 0, 0, 0, time.UTC), expected: date(2026, time.October, 17)},
+		{name: "Already passed this year", dateOfBirth: date(1990, time.January, 5), from: date(2026, time.October, 17), expected: date(2027, time.January, 5)},
+		{name: "Leapling before Feb 28 of non-leap year", dateOfBirth: date(2000, time.February, 29), from: date(2026, time.January, 10), policy: PolicyFeb28, expected: date(2026, time.February, 28)},
+		{name: "Leapling after Feb 28, next year is leap", dateOfBirth: date(2000, time.February, 29), from: date(2027, time.March, 2), policy: PolicyFeb28, expected: date(2028, time.February, 29)},
+		{name: "Leapling on Mar 1 with mar1 policy", dateOfBirth: date(2000, time.February, 29), from: date(2027, time.March, 1), policy: PolicyMar1, expected: date(2027, time.March, 1)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Next(tt.dateOfBirth, tt.from, tt.policy))
+		})
+	}
+}
+
+func TestAge(t *testing.T) {
+	assert.Equal(t, 26, Age(date(2000, time.February, 29), date(2026, time.March, 1)))
+	assert.Equal(t, 36, Age(date(1990, time.October, 18), date(2026, time.October, 18)))
+}
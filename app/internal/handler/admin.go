@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"birthdayReminder/internal/handler/dead_letter"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/user"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// currentUser загружает пользователя, прошедшего Authenticate; при ошибке ответ уже записан.
+func (h *Handler) currentUser(w http.ResponseWriter, r *http.Request) (*user.User, bool) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	dbUser, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		log.Println("Error fetching user:", err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return dbUser, true
+}
+
+// requireAdmin проверяет токен и права администратора; при отказе ответ уже записан.
+func (h *Handler) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return false
+	}
+
+	if !dbUser.IsAdmin {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// GetDeadNotifications /api/admin/notifications/dead
+func (h *Handler) GetDeadNotifications(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling get dead notifications request")
+
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	notifications, err := h.notificationRepo.GetDead()
+	if err != nil {
+		log.Println("Error fetching dead notifications:", err)
+		http.Error(w, "Error fetching dead notifications", http.StatusInternalServerError)
+		return
+	}
+
+	deadNotifications := make([]dead_letter.ResponseDto, 0, len(notifications))
+	for _, n := range notifications {
+		dto := dead_letter.ResponseDto{
+			ID:           n.ID,
+			SubscriberID: n.SubscriberID,
+			CelebrantID:  n.CelebrantID,
+			Kind:         n.Kind,
+			BirthdayYear: n.BirthdayYear,
+			Channel:      n.Channel,
+			Attempts:     n.Attempts,
+			UpdatedAt:    n.UpdatedAt,
+		}
+		if n.LastError != nil {
+			dto.LastError = *n.LastError
+		}
+		deadNotifications = append(deadNotifications, dto)
+	}
+
+	response, err := json.Marshal(deadNotifications)
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// RetryNotification /api/admin/notifications/{id}/retry
+func (h *Handler) RetryNotification(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling retry notification request")
+
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.notificationRepo.Requeue(id); err != nil {
+		if errors.Is(err, notification.ErrNotFound) {
+			http.Error(w, "Dead notification not found", http.StatusNotFound)
+		} else {
+			log.Println("Error requeueing notification:", err)
+			http.Error(w, "Error requeueing notification", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte("Notification queued for retry"))
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"birthdayReminder/internal/handler/announcements"
+	"birthdayReminder/internal/notifier"
+	"birthdayReminder/internal/repository/announcement"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxAnnouncementDaysAhead = 60
+
+func announcementResponse(c announcement.Channel) announcements.ResponseDto {
+	return announcements.ResponseDto{
+		ID:        c.ID,
+		Name:      c.Name,
+		URL:       c.URL,
+		Format:    c.Format,
+		DaysAhead: c.DaysAhead,
+		TimeZone:  c.TimeZone,
+		PostHour:  c.PostHour,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// CreateAnnouncementChannel /api/admin/announcements
+func (h *Handler) CreateAnnouncementChannel(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling create announcement channel request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+	if !dbUser.IsAdmin {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var reqBody announcements.RequestDto
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		log.Println("Error decoding request body:", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing request body: %v", err)
+		}
+	}(r.Body)
+
+	reqBody.Name = strings.TrimSpace(reqBody.Name)
+	if reqBody.Name == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+	if !validWebhookURL(reqBody.URL, h.AllowPrivateTargets) {
+		http.Error(w, "Invalid webhook URL", http.StatusBadRequest)
+		return
+	}
+	if reqBody.Format == "" {
+		reqBody.Format = notifier.DefaultAnnouncementFormat
+	}
+	if _, err := notifier.ParseAnnouncementFormat(reqBody.Format); err != nil {
+		http.Error(w, "Invalid format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if reqBody.TimeZone == "" {
+		reqBody.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(reqBody.TimeZone); err != nil {
+		http.Error(w, "Invalid time zone", http.StatusBadRequest)
+		return
+	}
+	if reqBody.PostHour < 0 || reqBody.PostHour > 23 {
+		http.Error(w, "Post hour must be between 0 and 23", http.StatusBadRequest)
+		return
+	}
+	if reqBody.DaysAhead < 0 || reqBody.DaysAhead > maxAnnouncementDaysAhead {
+		http.Error(w, "Days ahead must be between 0 and 60", http.StatusBadRequest)
+		return
+	}
+
+	channel := announcement.Channel{
+		Name:      reqBody.Name,
+		URL:       reqBody.URL,
+		Format:    reqBody.Format,
+		DaysAhead: reqBody.DaysAhead,
+		TimeZone:  reqBody.TimeZone,
+		PostHour:  reqBody.PostHour,
+		CreatedBy: dbUser.ID,
+	}
+	if err := h.announcementRepo.Create(&channel); err != nil {
+		log.Println("Error creating announcement channel:", err)
+		http.Error(w, "Error creating announcement channel", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := json.Marshal(announcementResponse(channel))
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// GetAnnouncementChannels /api/admin/announcements
+func (h *Handler) GetAnnouncementChannels(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling get announcement channels request")
+
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	channels, err := h.announcementRepo.GetAll()
+	if err != nil {
+		log.Println("Error fetching announcement channels:", err)
+		http.Error(w, "Error fetching announcement channels", http.StatusInternalServerError)
+		return
+	}
+
+	dtos := make([]announcements.ResponseDto, 0, len(channels))
+	for _, c := range channels {
+		dtos = append(dtos, announcementResponse(c))
+	}
+
+	response, err := json.Marshal(dtos)
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// DeleteAnnouncementChannel /api/admin/announcements/{id}
+func (h *Handler) DeleteAnnouncementChannel(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling delete announcement channel request")
+
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid announcement channel ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.announcementRepo.Delete(id); err != nil {
+		if errors.Is(err, announcement.ErrNotFound) {
+			http.Error(w, "Announcement channel not found", http.StatusNotFound)
+		} else {
+			log.Println("Error deleting announcement channel:", err)
+			http.Error(w, "Error deleting announcement channel", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte("Announcement channel deleted"))
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
@@ -0,0 +1,24 @@
+package announcements
+
+import "time"
+
+type RequestDto struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Format - шаблон сообщения; пустой - формат по умолчанию.
+	Format    string `json:"format"`
+	DaysAhead int    `json:"days_ahead"`
+	TimeZone  string `json:"time_zone"`
+	PostHour  int    `json:"post_hour"`
+}
+
+type ResponseDto struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Format    string    `json:"format"`
+	DaysAhead int       `json:"days_ahead"`
+	TimeZone  string    `json:"time_zone"`
+	PostHour  int       `json:"post_hour"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/pkg/errors"
@@ -8,50 +10,107 @@ import (
 	"time"
 )
 
+// Issuer - значение iss в токенах сервиса; его проверяют и другие сервисы, принимающие эти токены.
+const Issuer = "birthday-reminder"
+
 type TokenService struct {
+	// Keys - ключи подписи и проверки, открытая часть публикуется в /.well-known/jwks.json.
+	Keys *KeySet
+	// Denylist - отозванные токены; nil отключает проверку отзыва.
+	Denylist *Denylist
 }
 
 type Claims struct {
 	UserID int `json:"user_id"`
+	// IssuedAtMs - время выдачи в миллисекундах; iat хранит только секунды,
+	// а выход на всех устройствах должен отличать токены, выданные в ту же секунду.
+	IssuedAtMs int64 `json:"iat_ms,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func (t *TokenService) GenerateJWT(userID int, secretKey string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+// issuedAt возвращает время выдачи токена с наибольшей известной точностью.
+func (c *Claims) issuedAt() (time.Time, bool) {
+	if c.IssuedAtMs != 0 {
+		return time.UnixMilli(c.IssuedAtMs), true
+	}
+	if c.IssuedAt != nil {
+		return c.IssuedAt.Time, true
+	}
+	return time.Time{}, false
+}
+
+func (t *TokenService) GenerateJWT(userID int) (string, error) {
+	// jti позволяет отозвать конкретный токен при выходе
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %v", err)
+	}
+
+	now := time.Now()
 	claims := &Claims{
-		UserID: userID,
+		UserID:     userID,
+		IssuedAtMs: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			ID:        hex.EncodeToString(jti),
+			Issuer:    Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
 		},
 	}
 
-	// Создаем новый JWT токен с указанными claims и методом подписи HS256
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Подписываем токен с использованием секретного ключа и возвращаем подписанную строку токена
-	signedToken, err := token.SignedString([]byte(secretKey))
+	// Подписываем текущим ключом набора (RS256 или EdDSA), kid в заголовке указывает ключ проверки
+	signedToken, err := t.Keys.sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign JWT token: %v", err)
 	}
 	return signedToken, nil
 }
 
-func (t *TokenService) ParseJWT(tokenStr string, secretKey string) (*Claims, error) {
+func (t *TokenService) ParseJWT(tokenStr string) (*Claims, error) {
 	claims := &Claims{}
 	// ParseWithClaims разбирает токен и заполняет структуру claims.
-	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenStr, claims, t.Keys.keyFunc,
+		jwt.WithValidMethods(t.Keys.methods()),
+		jwt.WithIssuer(Issuer),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		log.Println("Error parsing JWT:", err)
 		return nil, errors.New("invalid token")
 	}
 
-	if !token.Valid {
+	// у ссылки подтверждения email нет user_id, поэтому как access-токен она не пройдет
+	if !token.Valid || claims.UserID == 0 {
 		log.Println("Invalid JWT token")
 		return nil, errors.New("invalid token")
 	}
 
+	if t.Denylist != nil && t.Denylist.IsRevoked(claims) {
+		log.Printf("Revoked JWT used by user ID %d\n", claims.UserID)
+		return nil, errors.New("token revoked")
+	}
+
 	return claims, nil
 }
+
+// RevokeJWT отзывает токен, из которого получены claims.
+func (t *TokenService) RevokeJWT(claims *Claims) error {
+	if t.Denylist == nil {
+		return errors.New("token revocation is not configured")
+	}
+	return t.Denylist.Revoke(claims)
+}
+
+// RevokeAllJWT отзывает все выданные пользователю токены.
+func (t *TokenService) RevokeAllJWT(userID int) error {
+	if t.Denylist == nil {
+		return errors.New("token revocation is not configured")
+	}
+	return t.Denylist.RevokeUser(userID)
+}
+
+// JWKS возвращает открытые ключи, которыми можно проверить токены сервиса.
+func (t *TokenService) JWKS() JWKSet {
+	return t.Keys.JWKS()
+}
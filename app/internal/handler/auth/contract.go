@@ -1,7 +1,22 @@
 package auth
 
+import "time"
+
 //go:generate mockgen -source=contract.go -destination=mocks/mockTokenManager.go
 type TokenManager interface {
-	GenerateJWT(userID int, secretKey string) (string, error)
-	ParseJWT(tokenStr string, secretKey string) (*Claims, error)
+	GenerateJWT(userID int) (string, error)
+	ParseJWT(tokenStr string) (*Claims, error)
+	RevokeJWT(claims *Claims) error
+	RevokeAllJWT(userID int) error
+	JWKS() JWKSet
+	GenerateEmailVerificationToken(userID int, email string) (token string, tokenID string, err error)
+	ParseEmailVerificationToken(tokenStr string) (*EmailVerificationClaims, error)
+}
+
+type RevocationRepository interface {
+	RevokeToken(jti string, userID int, expiresAt time.Time) error
+	RevokeUserTokens(userID int, before time.Time) error
+	GetRevokedTokens() (map[string]time.Time, error)
+	GetUserRevocations(since time.Time) (map[int]time.Time, error)
+	DeleteExpired(since time.Time) error
 }
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Denylist хранит отозванные токены в памяти. Снимок из базы перечитывается не реже
+// раза в refreshInterval, чтобы выход, сделанный через другой экземпляр сервиса, действовал и здесь.
+type Denylist struct {
+	repo            RevocationRepository
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu       sync.RWMutex
+	tokens   map[string]time.Time
+	users    map[int]time.Time
+	loadedAt time.Time
+}
+
+func NewDenylist(repo RevocationRepository, refreshInterval time.Duration) *Denylist {
+	return &Denylist{
+		repo:            repo,
+		refreshInterval: refreshInterval,
+		now:             time.Now,
+		tokens:          make(map[string]time.Time),
+		users:           make(map[int]time.Time),
+	}
+}
+
+// Revoke отзывает один токен до истечения его срока.
+func (d *Denylist) Revoke(claims *Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return errors.New("token has no jti or expiration")
+	}
+	if err := d.repo.RevokeToken(claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.tokens[claims.ID] = claims.ExpiresAt.Time
+	d.mu.Unlock()
+	return nil
+}
+
+// RevokeUser отзывает все токены пользователя, выданные до текущего момента.
+func (d *Denylist) RevokeUser(userID int) error {
+	before := d.now()
+	if err := d.repo.RevokeUserTokens(userID, before); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	if before.After(d.users[userID]) {
+		d.users[userID] = before
+	}
+	d.mu.Unlock()
+	return nil
+}
+
+// IsRevoked сообщает, отозван ли токен.
+func (d *Denylist) IsRevoked(claims *Claims) bool {
+	d.refreshIfStale()
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if _, ok := d.tokens[claims.ID]; ok && claims.ID != "" {
+		return true
+	}
+	if before, ok := d.users[claims.UserID]; ok {
+		// токены без iat_ms известны с точностью до секунды: выданный в секунду выхода тоже отозван
+		issuedAt, ok := claims.issuedAt()
+		return !ok || !issuedAt.After(before)
+	}
+	return false
+}
+
+// refreshIfStale дополняет кэш записями из базы и выбрасывает истекшие.
+// Записи только добавляются, поэтому отзыв, сделанный во время загрузки, не теряется.
+func (d *Denylist) refreshIfStale() {
+	now := d.now()
+
+	d.mu.Lock()
+	if now.Sub(d.loadedAt) < d.refreshInterval {
+		d.mu.Unlock()
+		return
+	}
+	// остальные запросы не ждут загрузки и пока проверяются по старому снимку
+	d.loadedAt = now
+	d.mu.Unlock()
+
+	// токены старше AccessTokenTTL истекли сами, отзывать их уже не нужно
+	since := now.Add(-AccessTokenTTL)
+	if err := d.repo.DeleteExpired(since); err != nil {
+		log.Println("Error deleting expired token revocations:", err)
+	}
+	tokens, err := d.repo.GetRevokedTokens()
+	if err != nil {
+		log.Println("Error loading revoked tokens:", err)
+		return
+	}
+	users, err := d.repo.GetUserRevocations(since)
+	if err != nil {
+		log.Println("Error loading user token revocations:", err)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for jti, expiresAt := range d.tokens {
+		if !expiresAt.After(now) {
+			delete(d.tokens, jti)
+		}
+	}
+	for jti, expiresAt := range tokens {
+		d.tokens[jti] = expiresAt
+	}
+	for userID, before := range d.users {
+		if !before.After(since) {
+			delete(d.users, userID)
+		}
+	}
+	for userID, before := range users {
+		if before.After(d.users[userID]) {
+			d.users[userID] = before
+		}
+	}
+}
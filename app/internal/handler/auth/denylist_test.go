@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// memoryRevocations - хранилище отзывов в памяти, общее для нескольких Denylist как база для экземпляров сервиса.
+type memoryRevocations struct {
+	tokens map[string]time.Time
+	users  map[int]time.Time
+	loads  int
+}
+
+func newMemoryRevocations() *memoryRevocations {
+	return &memoryRevocations{tokens: make(map[string]time.Time), users: make(map[int]time.Time)}
+}
+
+func (m *memoryRevocations) RevokeToken(jti string, userID int, expiresAt time.Time) error {
+	m.tokens[jti] = expiresAt
+	return nil
+}
+
+func (m *memoryRevocations) RevokeUserTokens(userID int, before time.Time) error {
+	m.users[userID] = before
+	return nil
+}
+
+func (m *memoryRevocations) GetRevokedTokens() (map[string]time.Time, error) {
+	m.loads++
+	tokens := make(map[string]time.Time, len(m.tokens))
+	for jti, expiresAt := range m.tokens {
+		tokens[jti] = expiresAt
+	}
+	return tokens, nil
+}
+
+func (m *memoryRevocations) GetUserRevocations(since time.Time) (map[int]time.Time, error) {
+	users := make(map[int]time.Time, len(m.users))
+	for userID, before := range m.users {
+		if before.After(since) {
+			users[userID] = before
+		}
+	}
+	return users, nil
+}
+
+func (m *memoryRevocations) DeleteExpired(since time.Time) error {
+	return nil
+}
+
+func TestTokenServiceRevokeJWT(t *testing.T) {
+	service := &TokenService{Keys: testKeySet(t), Denylist: NewDenylist(newMemoryRevocations(), time.Minute)}
+
+	first, err := service.GenerateJWT(1)
+	require.NoError(t, err)
+	second, err := service.GenerateJWT(1)
+	require.NoError(t, err)
+
+	claims, err := service.ParseJWT(first)
+	require.NoError(t, err)
+	assert.NotEmpty(t, claims.ID)
+	require.NoError(t, service.RevokeJWT(claims))
+
+	_, err = service.ParseJWT(first)
+	assert.EqualError(t, err, "token revoked")
+
+	// другая сессия того же пользователя продолжает работать
+	_, err = service.ParseJWT(second)
+	assert.NoError(t, err)
+}
+
+func TestDenylistRevokeUser(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC)
+	denylist := NewDenylist(newMemoryRevocations(), time.Minute)
+	denylist.now = func() time.Time { return now }
+
+	issued := func(at time.Time) *Claims {
+		return &Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{ID: at.String(), IssuedAt: jwt.NewNumericDate(at)}}
+	}
+
+	require.NoError(t, denylist.RevokeUser(1))
+
+	assert.True(t, denylist.IsRevoked(issued(now.Add(-time.Minute))))
+	assert.True(t, denylist.IsRevoked(&Claims{UserID: 1}), "token without iat")
+	assert.False(t, denylist.IsRevoked(issued(now.Add(time.Second))))
+	assert.False(t, denylist.IsRevoked(&Claims{UserID: 2, RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(now)}}))
+}
+
+func TestDenylistRevokeUserInSameSecond(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 10, 0, 0, 300*int(time.Millisecond), time.UTC)
+	denylist := NewDenylist(newMemoryRevocations(), time.Minute)
+	denylist.now = func() time.Time { return now }
+
+	issued := func(at time.Time) *Claims {
+		return &Claims{UserID: 1, IssuedAtMs: at.UnixMilli(), RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(at)}}
+	}
+
+	require.NoError(t, denylist.RevokeUser(1))
+
+	// iat у обоих токенов одинаковый, различает их только iat_ms
+	assert.True(t, denylist.IsRevoked(issued(now.Add(-200*time.Millisecond))), "issued earlier in the same second")
+	assert.False(t, denylist.IsRevoked(issued(now.Add(200*time.Millisecond))), "login right after logout")
+
+	// без iat_ms токен, выданный в секунду выхода, считается отозванным
+	legacy := &Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(now.Add(200 * time.Millisecond))}}
+	assert.True(t, denylist.IsRevoked(legacy))
+}
+
+func TestDenylistPicksUpRevocationsFromOtherInstances(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC)
+	repo := newMemoryRevocations()
+	local := NewDenylist(repo, time.Minute)
+	local.now = func() time.Time { return now }
+	remote := NewDenylist(repo, time.Minute)
+	remote.now = func() time.Time { return now }
+
+	claims := &Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{ID: "abc", ExpiresAt: jwt.NewNumericDate(now.Add(10 * time.Minute))}}
+	assert.False(t, local.IsRevoked(claims))
+	require.NoError(t, remote.Revoke(claims))
+
+	// до перечитывания снимка экземпляр не знает об отзыве и не ходит в базу
+	assert.False(t, local.IsRevoked(claims))
+	assert.Equal(t, 1, repo.loads)
+
+	now = now.Add(time.Minute)
+	assert.True(t, local.IsRevoked(claims))
+
+	// после истечения токена запись выбрасывается из кэша
+	now = now.Add(10 * time.Minute)
+	delete(repo.tokens, "abc")
+	local.refreshIfStale()
+	assert.Empty(t, local.tokens)
+}
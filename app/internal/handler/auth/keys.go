@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// minRSABits - RSA-ключи короче 2048 бит не принимаются ни для подписи, ни для проверки.
+const minRSABits = 2048
+
+// JWK - открытый ключ в формате RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKSet - содержимое /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type verificationKey struct {
+	jwk    JWK
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+// KeySet - ключ, которым подписываются новые токены, и все ключи, которыми токены проверяются.
+// При ротации прежний ключ остается в наборе проверки, пока не истекут подписанные им токены.
+type KeySet struct {
+	signingKey   crypto.Signer
+	signing      *verificationKey
+	verification map[string]*verificationKey
+	order        []string
+}
+
+// NewKeySet собирает набор из закрытого ключа подписи (RSA или Ed25519) и дополнительных открытых ключей проверки.
+func NewKeySet(signingKey crypto.Signer, verificationKeys ...crypto.PublicKey) (*KeySet, error) {
+	signing, err := newVerificationKey(signingKey.Public())
+	if err != nil {
+		return nil, fmt.Errorf("signing key: %w", err)
+	}
+
+	ks := &KeySet{signingKey: signingKey, signing: signing, verification: make(map[string]*verificationKey)}
+	ks.add(signing)
+	for _, public := range verificationKeys {
+		key, err := newVerificationKey(public)
+		if err != nil {
+			return nil, fmt.Errorf("verification key: %w", err)
+		}
+		ks.add(key)
+	}
+	return ks, nil
+}
+
+// LoadKeySet читает ключи из PEM-файлов: закрытый ключ подписи и открытые (или закрытые) ключи предыдущих поколений.
+func LoadKeySet(signingKeyFile string, verificationKeyFiles []string) (*KeySet, error) {
+	data, err := os.ReadFile(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	signingKey, err := ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", signingKeyFile, err)
+	}
+
+	var verificationKeys []crypto.PublicKey
+	for _, file := range verificationKeyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		public, err := parsePublicKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		verificationKeys = append(verificationKeys, public)
+	}
+
+	return NewKeySet(signingKey, verificationKeys...)
+}
+
+// KeySetFromEnv загружает ключи по путям из JWT_SIGNING_KEY_FILE и JWT_VERIFICATION_KEY_FILES (через запятую).
+func KeySetFromEnv() (*KeySet, error) {
+	signingKeyFile := os.Getenv("JWT_SIGNING_KEY_FILE")
+	if signingKeyFile == "" {
+		return nil, errors.New("JWT_SIGNING_KEY_FILE is not set")
+	}
+
+	var verificationKeyFiles []string
+	for _, file := range strings.Split(os.Getenv("JWT_VERIFICATION_KEY_FILES"), ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			verificationKeyFiles = append(verificationKeyFiles, file)
+		}
+	}
+	return LoadKeySet(signingKeyFile, verificationKeyFiles)
+}
+
+func (ks *KeySet) add(key *verificationKey) {
+	if _, ok := ks.verification[key.jwk.KeyID]; ok {
+		return
+	}
+	ks.verification[key.jwk.KeyID] = key
+	ks.order = append(ks.order, key.jwk.KeyID)
+}
+
+// sign подписывает claims текущим ключом и указывает его kid в заголовке.
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.method, claims)
+	token.Header["kid"] = ks.signing.jwk.KeyID
+	return token.SignedString(ks.signingKey)
+}
+
+// keyFunc выбирает ключ проверки по kid и не дает подменить алгоритм подписи.
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.verification[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("key %q does not accept %s", kid, token.Method.Alg())
+	}
+	return key.public, nil
+}
+
+func (ks *KeySet) methods() []string {
+	return []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+}
+
+// JWKS возвращает открытые ключи проверки; ключ подписи идет первым.
+func (ks *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(ks.order))}
+	for _, kid := range ks.order {
+		set.Keys = append(set.Keys, ks.verification[kid].jwk)
+	}
+	return set
+}
+
+func newVerificationKey(public crypto.PublicKey) (*verificationKey, error) {
+	var key verificationKey
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSABits)
+		}
+		key = verificationKey{
+			method: jwt.SigningMethodRS256,
+			public: pub,
+			jwk: JWK{
+				KeyType: "RSA",
+				N:       base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			},
+		}
+	case ed25519.PublicKey:
+		key = verificationKey{
+			method: jwt.SigningMethodEdDSA,
+			public: pub,
+			jwk: JWK{
+				KeyType: "OKP",
+				Curve:   "Ed25519",
+				X:       base64.RawURLEncoding.EncodeToString(pub),
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", public)
+	}
+
+	key.jwk.Use = "sig"
+	key.jwk.Algorithm = key.method.Alg()
+	key.jwk.KeyID = thumbprint(key.jwk)
+	return &key, nil
+}
+
+// thumbprint - отпечаток ключа по RFC 7638; служит kid, поэтому его не нужно настраивать отдельно.
+func thumbprint(jwk JWK) string {
+	var members []byte
+	// encoding/json сортирует ключи map, как того требует RFC 7638
+	if jwk.KeyType == "RSA" {
+		members, _ = json.Marshal(map[string]string{"e": jwk.E, "kty": jwk.KeyType, "n": jwk.N})
+	} else {
+		members, _ = json.Marshal(map[string]string{"crv": jwk.Curve, "kty": jwk.KeyType, "x": jwk.X})
+	}
+	sum := sha256.Sum256(members)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ParsePrivateKeyPEM разбирает закрытый ключ RSA или Ed25519 в PKCS#8 или PKCS#1.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", key)
+	}
+}
+
+// parsePublicKeyPEM принимает открытый ключ или закрытый, из которого берется открытая часть.
+func parsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		signer, err := ParsePrivateKeyPEM(data)
+		if err != nil {
+			return nil, err
+		}
+		return signer.Public(), nil
+	}
+}
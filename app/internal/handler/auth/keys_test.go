@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func testKeySet(t *testing.T) *KeySet {
+	ks, err := NewKeySet(newEd25519Key(t))
+	require.NoError(t, err)
+	return ks
+}
+
+func TestSignAndParse(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	for name, key := range map[string]crypto.Signer{"RS256": rsaKey, "EdDSA": newEd25519Key(t)} {
+		t.Run(name, func(t *testing.T) {
+			ks, err := NewKeySet(key)
+			require.NoError(t, err)
+			service := &TokenService{Keys: ks}
+
+			tokenStr, err := service.GenerateJWT(7)
+			require.NoError(t, err)
+
+			token, _, err := jwt.NewParser().ParseUnverified(tokenStr, &Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, name, token.Header["alg"])
+			assert.Equal(t, ks.JWKS().Keys[0].KeyID, token.Header["kid"])
+
+			claims, err := service.ParseJWT(tokenStr)
+			require.NoError(t, err)
+			assert.Equal(t, 7, claims.UserID)
+			assert.Equal(t, Issuer, claims.Issuer)
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey, newKey := newEd25519Key(t), newEd25519Key(t)
+
+	before, err := NewKeySet(oldKey)
+	require.NoError(t, err)
+	oldToken, err := (&TokenService{Keys: before}).GenerateJWT(1)
+	require.NoError(t, err)
+
+	// новый ключ подписи, старый оставлен только для проверки
+	after, err := NewKeySet(newKey, oldKey.Public())
+	require.NoError(t, err)
+	service := &TokenService{Keys: after}
+	newToken, err := service.GenerateJWT(1)
+	require.NoError(t, err)
+
+	_, err = service.ParseJWT(oldToken)
+	assert.NoError(t, err)
+	_, err = service.ParseJWT(newToken)
+	assert.NoError(t, err)
+	assert.Len(t, after.JWKS().Keys, 2)
+
+	// после удаления старого ключа подписанные им токены не принимаются
+	retired, err := NewKeySet(newKey)
+	require.NoError(t, err)
+	_, err = (&TokenService{Keys: retired}).ParseJWT(oldToken)
+	assert.Error(t, err)
+}
+
+func TestParseRejectsForeignTokens(t *testing.T) {
+	ks := testKeySet(t)
+	service := &TokenService{Keys: ks}
+	kid := ks.JWKS().Keys[0].KeyID
+	claims := &Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{
+		Issuer:    Issuer,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}}
+
+	// HS256 с открытым ключом в роли секрета - классическая подмена алгоритма
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hs.Header["kid"] = kid
+	forged, err := hs.SignedString([]byte(ks.signing.public.(ed25519.PublicKey)))
+	require.NoError(t, err)
+	_, err = service.ParseJWT(forged)
+	assert.Error(t, err)
+
+	// чужой ключ с тем же kid
+	other := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	other.Header["kid"] = kid
+	forged, err = other.SignedString(newEd25519Key(t))
+	require.NoError(t, err)
+	_, err = service.ParseJWT(forged)
+	assert.Error(t, err)
+
+	// токен без срока действия
+	noExpiry, err := ks.sign(&Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{Issuer: Issuer}})
+	require.NoError(t, err)
+	_, err = service.ParseJWT(noExpiry)
+	assert.Error(t, err)
+}
+
+func TestThumbprint(t *testing.T) {
+	// пример из RFC 7638, раздел 3.1
+	n := "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"
+	modulus, err := base64.RawURLEncoding.DecodeString(n)
+	require.NoError(t, err)
+
+	key, err := newVerificationKey(&rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: 65537})
+	require.NoError(t, err)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", key.jwk.KeyID)
+	assert.Equal(t, "AQAB", key.jwk.E)
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+		return path
+	}
+
+	current := newEd25519Key(t)
+	der, err := x509.MarshalPKCS8PrivateKey(current)
+	require.NoError(t, err)
+	signingFile := writePEM("current.pem", "PRIVATE KEY", der)
+
+	previous, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(&previous.PublicKey)
+	require.NoError(t, err)
+	previousFile := writePEM("previous.pub.pem", "PUBLIC KEY", der)
+
+	ks, err := LoadKeySet(signingFile, []string{previousFile})
+	require.NoError(t, err)
+	jwks := ks.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Algorithm)
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "RS256", jwks.Keys[1].Algorithm)
+
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	weakFile := writePEM("weak.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(weak))
+	_, err = LoadKeySet(weakFile, nil)
+	assert.Error(t, err)
+}
@@ -5,8 +5,9 @@
 package mock_auth
 
 import (
-	auth "birthdayReminder/app/internal/handler/auth"
+	auth "birthdayReminder/internal/handler/auth"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,32 +35,200 @@ func (m *MockTokenManager) EXPECT() *MockTokenManagerMockRecorder {
 	return m.recorder
 }
 
+// GenerateEmailVerificationToken mocks base method.
+func (m *MockTokenManager) GenerateEmailVerificationToken(userID int, email string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateEmailVerificationToken", userID, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GenerateEmailVerificationToken indicates an expected call of GenerateEmailVerificationToken.
+func (mr *MockTokenManagerMockRecorder) GenerateEmailVerificationToken(userID, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateEmailVerificationToken", reflect.TypeOf((*MockTokenManager)(nil).GenerateEmailVerificationToken), userID, email)
+}
+
 // GenerateJWT mocks base method.
-func (m *MockTokenManager) GenerateJWT(userID int, secretKey string) (string, error) {
+func (m *MockTokenManager) GenerateJWT(userID int) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateJWT", userID, secretKey)
+	ret := m.ctrl.Call(m, "GenerateJWT", userID)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateJWT indicates an expected call of GenerateJWT.
-func (mr *MockTokenManagerMockRecorder) GenerateJWT(userID, secretKey interface{}) *gomock.Call {
+func (mr *MockTokenManagerMockRecorder) GenerateJWT(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateJWT", reflect.TypeOf((*MockTokenManager)(nil).GenerateJWT), userID)
+}
+
+// JWKS mocks base method.
+func (m *MockTokenManager) JWKS() auth.JWKSet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(auth.JWKSet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockTokenManagerMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockTokenManager)(nil).JWKS))
+}
+
+// ParseEmailVerificationToken mocks base method.
+func (m *MockTokenManager) ParseEmailVerificationToken(tokenStr string) (*auth.EmailVerificationClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseEmailVerificationToken", tokenStr)
+	ret0, _ := ret[0].(*auth.EmailVerificationClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseEmailVerificationToken indicates an expected call of ParseEmailVerificationToken.
+func (mr *MockTokenManagerMockRecorder) ParseEmailVerificationToken(tokenStr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateJWT", reflect.TypeOf((*MockTokenManager)(nil).GenerateJWT), userID, secretKey)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseEmailVerificationToken", reflect.TypeOf((*MockTokenManager)(nil).ParseEmailVerificationToken), tokenStr)
 }
 
 // ParseJWT mocks base method.
-func (m *MockTokenManager) ParseJWT(tokenStr, secretKey string) (*auth.Claims, error) {
+func (m *MockTokenManager) ParseJWT(tokenStr string) (*auth.Claims, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ParseJWT", tokenStr, secretKey)
+	ret := m.ctrl.Call(m, "ParseJWT", tokenStr)
 	ret0, _ := ret[0].(*auth.Claims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ParseJWT indicates an expected call of ParseJWT.
-func (mr *MockTokenManagerMockRecorder) ParseJWT(tokenStr, secretKey interface{}) *gomock.Call {
+func (mr *MockTokenManagerMockRecorder) ParseJWT(tokenStr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseJWT", reflect.TypeOf((*MockTokenManager)(nil).ParseJWT), tokenStr)
+}
+
+// RevokeAllJWT mocks base method.
+func (m *MockTokenManager) RevokeAllJWT(userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllJWT", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllJWT indicates an expected call of RevokeAllJWT.
+func (mr *MockTokenManagerMockRecorder) RevokeAllJWT(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllJWT", reflect.TypeOf((*MockTokenManager)(nil).RevokeAllJWT), userID)
+}
+
+// RevokeJWT mocks base method.
+func (m *MockTokenManager) RevokeJWT(claims *auth.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeJWT", claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeJWT indicates an expected call of RevokeJWT.
+func (mr *MockTokenManagerMockRecorder) RevokeJWT(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeJWT", reflect.TypeOf((*MockTokenManager)(nil).RevokeJWT), claims)
+}
+
+// MockRevocationRepository is a mock of RevocationRepository interface.
+type MockRevocationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationRepositoryMockRecorder
+}
+
+// MockRevocationRepositoryMockRecorder is the mock recorder for MockRevocationRepository.
+type MockRevocationRepositoryMockRecorder struct {
+	mock *MockRevocationRepository
+}
+
+// NewMockRevocationRepository creates a new mock instance.
+func NewMockRevocationRepository(ctrl *gomock.Controller) *MockRevocationRepository {
+	mock := &MockRevocationRepository{ctrl: ctrl}
+	mock.recorder = &MockRevocationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationRepository) EXPECT() *MockRevocationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockRevocationRepository) DeleteExpired(since time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", since)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockRevocationRepositoryMockRecorder) DeleteExpired(since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockRevocationRepository)(nil).DeleteExpired), since)
+}
+
+// GetRevokedTokens mocks base method.
+func (m *MockRevocationRepository) GetRevokedTokens() (map[string]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevokedTokens")
+	ret0, _ := ret[0].(map[string]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevokedTokens indicates an expected call of GetRevokedTokens.
+func (mr *MockRevocationRepositoryMockRecorder) GetRevokedTokens() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevokedTokens", reflect.TypeOf((*MockRevocationRepository)(nil).GetRevokedTokens))
+}
+
+// GetUserRevocations mocks base method.
+func (m *MockRevocationRepository) GetUserRevocations(since time.Time) (map[int]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRevocations", since)
+	ret0, _ := ret[0].(map[int]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRevocations indicates an expected call of GetUserRevocations.
+func (mr *MockRevocationRepositoryMockRecorder) GetUserRevocations(since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRevocations", reflect.TypeOf((*MockRevocationRepository)(nil).GetUserRevocations), since)
+}
+
+// RevokeToken mocks base method.
+func (m *MockRevocationRepository) RevokeToken(jti string, userID int, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", jti, userID, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockRevocationRepositoryMockRecorder) RevokeToken(jti, userID, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockRevocationRepository)(nil).RevokeToken), jti, userID, expiresAt)
+}
+
+// RevokeUserTokens mocks base method.
+func (m *MockRevocationRepository) RevokeUserTokens(userID int, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserTokens", userID, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserTokens indicates an expected call of RevokeUserTokens.
+func (mr *MockRevocationRepositoryMockRecorder) RevokeUserTokens(userID, before interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseJWT", reflect.TypeOf((*MockTokenManager)(nil).ParseJWT), tokenStr, secretKey)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserTokens", reflect.TypeOf((*MockRevocationRepository)(nil).RevokeUserTokens), userID, before)
 }
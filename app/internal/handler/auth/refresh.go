@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	// AccessTokenTTL - срок жизни JWT; дольше сессию продлевает refresh-токен.
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+
+	refreshTokenBytes = 32
+	familyIDBytes     = 16
+)
+
+// NewRefreshToken возвращает непрозрачный refresh-токен для клиента и его хеш для базы.
+func NewRefreshToken() (token string, hash string, err error) {
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken - SHA-256 в hex; токен случайный, поэтому соль не нужна.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewFamilyID создает идентификатор цепочки ротаций для нового входа.
+func NewFamilyID() (string, error) {
+	raw := make([]byte, familyIDBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	// EmailVerificationAudience - aud ссылки подтверждения email, чтобы ее нельзя было предъявить как access-токен и наоборот.
+	EmailVerificationAudience = "email-verification"
+	EmailVerificationTTL      = 24 * time.Hour
+)
+
+// EmailVerificationClaims - содержимое ссылки подтверждения: пользователь в sub и адрес, который подтверждается.
+type EmailVerificationClaims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// UserID возвращает ID пользователя из sub, 0 если sub некорректен.
+func (c *EmailVerificationClaims) UserID() int {
+	id, err := strconv.Atoi(c.Subject)
+	if err != nil || id <= 0 {
+		return 0
+	}
+	return id
+}
+
+// GenerateEmailVerificationToken подписывает ссылку подтверждения адреса email для userID.
+// jti возвращается отдельно: одноразовость обеспечивает репозиторий, который хранит jti последней ссылки.
+func (t *TokenService) GenerateEmailVerificationToken(userID int, email string) (string, string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", "", fmt.Errorf("failed to generate token ID: %v", err)
+	}
+	tokenID := hex.EncodeToString(jti)
+
+	now := time.Now()
+	claims := &EmailVerificationClaims{
+		Email: email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   strconv.Itoa(userID),
+			Issuer:    Issuer,
+			Audience:  jwt.ClaimStrings{EmailVerificationAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(EmailVerificationTTL)),
+		},
+	}
+
+	signedToken, err := t.Keys.sign(claims)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign verification token: %v", err)
+	}
+	return signedToken, tokenID, nil
+}
+
+// ParseEmailVerificationToken проверяет подпись, срок и назначение ссылки подтверждения.
+func (t *TokenService) ParseEmailVerificationToken(tokenStr string) (*EmailVerificationClaims, error) {
+	claims := &EmailVerificationClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, t.Keys.keyFunc,
+		jwt.WithValidMethods(t.Keys.methods()),
+		jwt.WithIssuer(Issuer),
+		jwt.WithAudience(EmailVerificationAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		log.Println("Error parsing verification token:", err)
+		return nil, errors.New("invalid token")
+	}
+
+	if !token.Valid || claims.UserID() == 0 || claims.Email == "" || claims.ID == "" {
+		log.Println("Invalid verification token")
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestEmailVerificationToken(t *testing.T) {
+	ks := testKeySet(t)
+	service := &TokenService{Keys: ks}
+
+	token, tokenID, err := service.GenerateEmailVerificationToken(7, "john@example.com")
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokenID)
+
+	claims, err := service.ParseEmailVerificationToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, 7, claims.UserID())
+	assert.Equal(t, "john@example.com", claims.Email)
+	assert.Equal(t, tokenID, claims.ID)
+
+	// ссылка из письма не заменяет access-токен, и наоборот
+	_, err = service.ParseJWT(token)
+	assert.Error(t, err)
+	accessToken, err := service.GenerateJWT(7)
+	require.NoError(t, err)
+	_, err = service.ParseEmailVerificationToken(accessToken)
+	assert.Error(t, err)
+
+	expired, err := ks.sign(&EmailVerificationClaims{Email: "john@example.com", RegisteredClaims: jwt.RegisteredClaims{
+		ID:        "jti",
+		Subject:   "7",
+		Issuer:    Issuer,
+		Audience:  jwt.ClaimStrings{EmailVerificationAudience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+	}})
+	require.NoError(t, err)
+	_, err = service.ParseEmailVerificationToken(expired)
+	assert.Error(t, err)
+}
@@ -1,19 +1,92 @@
 package handler
 
 import (
+	"birthdayReminder/internal/repository/announcement"
+	"birthdayReminder/internal/repository/inbox"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/push"
+	"birthdayReminder/internal/repository/refresh"
+	"birthdayReminder/internal/repository/subscription"
+	"birthdayReminder/internal/repository/telegram"
 	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/repository/webhook"
+	"birthdayReminder/internal/stream"
+	"time"
 )
 
 //go:generate mockgen -source=contract.go -destination=mocks/mockRepo.go
 type UserRepository interface {
 	CreateUser(user *user.User, hashedPassword []byte) error
 	GetUserByEmail(email string) (*user.User, error)
+	GetUserByID(id int) (*user.User, error)
+	UpdateProfile(user *user.User) error
+	UpdatePreferences(user *user.User) error
 	GetAvailableUsersForSubscription(userID int) ([]user.User, error)
-	GetUsersWithBirthdayTomorrow() ([]user.User, error)
 	GetSubscribers(userID int) ([]user.User, error)
+	StartVerification(userID int, tokenID string, interval time.Duration) (bool, error)
+	VerifyEmail(userID int, email, tokenID string) (bool, error)
 }
 
 type SubscriptionRepository interface {
-	CreateSubscription(userID int, relatedUserID int) error
+	CreateSubscription(userID int, relatedUserID int, offsets []int) error
 	UnsubscribeUser(userID int, relatedUserID int) error
+	GetSubscriptions(userID int) ([]subscription.Subscription, error)
+	UpdateOffsets(id int, userID int, offsets []int) error
+	GetSubscription(id int, userID int) (*subscription.Subscription, error)
+	Snooze(id int, userID int, until *time.Time) error
+}
+
+type NotificationRepository interface {
+	GetDead() ([]notification.Notification, error)
+	Requeue(id int) error
+}
+
+type TelegramRepository interface {
+	CreateLinkCode(code telegram.LinkCode) error
+	Unlink(userID int) error
+}
+
+type WebhookRepository interface {
+	Create(w *webhook.Webhook) error
+	GetByID(id int) (*webhook.Webhook, error)
+	GetVisible(userID int, isAdmin bool) ([]webhook.Webhook, error)
+	Delete(id int) error
+	GetDeliveries(webhookID int, limit int) ([]webhook.Delivery, error)
+}
+
+type AnnouncementRepository interface {
+	Create(c *announcement.Channel) error
+	GetAll() ([]announcement.Channel, error)
+	Delete(id int) error
+}
+
+type InboxRepository interface {
+	List(userID int, unreadOnly bool, limit, offset int) ([]inbox.Item, error)
+	Counts(userID int) (total int, unread int, err error)
+	MarkRead(userID, id int) error
+	MarkAllRead(userID int) (int64, error)
+}
+
+type EventStream interface {
+	Subscribe(userID int) (events <-chan stream.Event, unsubscribe func())
+	Publish(e stream.Event) error
+}
+
+type PushRepository interface {
+	Save(s *push.Subscription) error
+	GetByUser(userID int) ([]push.Subscription, error)
+	Delete(userID, id int) error
+}
+
+// VerificationMailer отправляет письмо со ссылкой подтверждения email, в том числе на неподтвержденный адрес.
+type VerificationMailer interface {
+	SendVerification(recipient user.User, link string) error
+}
+
+type RefreshTokenRepository interface {
+	Create(t *refresh.Token) error
+	GetByHash(hash string) (*refresh.Token, error)
+	MarkUsed(id int) (bool, error)
+	RevokeFamily(familyID string) error
+	RevokeUser(userID int) error
 }
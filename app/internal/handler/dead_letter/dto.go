@@ -0,0 +1,15 @@
+package dead_letter
+
+import "time"
+
+type ResponseDto struct {
+	ID           int       `json:"id"`
+	SubscriberID int       `json:"subscriber_id"`
+	CelebrantID  int       `json:"celebrant_id,omitempty"`
+	Kind         string    `json:"kind"`
+	BirthdayYear int       `json:"birthday_year"`
+	Channel      string    `json:"channel"`
+	Attempts     int       `json:"attempts"`
+	LastError    string    `json:"last_error"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"birthdayReminder/internal/birthday"
 	"birthdayReminder/internal/handler/auth"
 	"birthdayReminder/internal/handler/available_user"
 	"birthdayReminder/internal/handler/login"
 	"birthdayReminder/internal/handler/subscribe"
+	"birthdayReminder/internal/netguard"
+	"birthdayReminder/internal/notifier"
 	"birthdayReminder/internal/repository/user"
 	"encoding/json"
 	"fmt"
@@ -12,7 +15,9 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/mail"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -20,14 +25,40 @@ import (
 // TODO добавить логи
 
 type Handler struct {
-	JWTSecretKey     string
-	userRepo         UserRepository
-	subscriptionRepo SubscriptionRepository
-	tokenManager     auth.TokenManager
+	TelegramBotName     string
+	VAPIDPublicKey      string
+	BaseURL             string
+	AllowPrivateTargets bool
+	userRepo            UserRepository
+	subscriptionRepo    SubscriptionRepository
+	notificationRepo    NotificationRepository
+	telegramRepo        TelegramRepository
+	webhookRepo         WebhookRepository
+	announcementRepo    AnnouncementRepository
+	inboxRepo           InboxRepository
+	pushRepo            PushRepository
+	refreshRepo         RefreshTokenRepository
+	mailer              VerificationMailer
+	events              EventStream
+	tokenManager        auth.TokenManager
 }
 
-func New(userRepo UserRepository, subscriptionRepo SubscriptionRepository, tokenManager auth.TokenManager) *Handler {
-	return &Handler{JWTSecretKey: os.Getenv("JWT_SECRET_KEY"), userRepo: userRepo, subscriptionRepo: subscriptionRepo, tokenManager: tokenManager}
+func New(userRepo UserRepository, subscriptionRepo SubscriptionRepository, notificationRepo NotificationRepository, telegramRepo TelegramRepository, webhookRepo WebhookRepository, announcementRepo AnnouncementRepository, inboxRepo InboxRepository, pushRepo PushRepository, refreshRepo RefreshTokenRepository, mailer VerificationMailer, events EventStream, tokenManager auth.TokenManager) *Handler {
+	baseURL := os.Getenv("APP_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Handler{TelegramBotName: os.Getenv("TELEGRAM_BOT_NAME"), VAPIDPublicKey: os.Getenv("VAPID_PUBLIC_KEY"), BaseURL: baseURL, AllowPrivateTargets: netguard.AllowPrivateFromEnv(), userRepo: userRepo, subscriptionRepo: subscriptionRepo, notificationRepo: notificationRepo, telegramRepo: telegramRepo, webhookRepo: webhookRepo, announcementRepo: announcementRepo, inboxRepo: inboxRepo, pushRepo: pushRepo, refreshRepo: refreshRepo, mailer: mailer, events: events, tokenManager: tokenManager}
+}
+
+// validEmail принимает только адрес без имени и угловых скобок: адрес попадает в заголовки писем,
+// поэтому переводы строки и прочие отличия от разобранного адреса не допускаются.
+func validEmail(email string) bool {
+	if strings.ContainsAny(email, "\r\n") {
+		return false
+	}
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
 }
 
 // Register /api/registration
@@ -49,6 +80,33 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !validEmail(user.Email) {
+		http.Error(w, "Invalid email", http.StatusBadRequest)
+		return
+	}
+
+	if user.TimeZone != "" {
+		if _, err := time.LoadLocation(user.TimeZone); err != nil {
+			http.Error(w, "Invalid time zone", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if user.LeapPolicy != "" && !birthday.Policy(user.LeapPolicy).Valid() {
+		http.Error(w, "Invalid leap policy", http.StatusBadRequest)
+		return
+	}
+
+	if user.Locale != "" && !notifier.SupportedLocale(user.Locale) {
+		http.Error(w, "Unsupported locale", http.StatusBadRequest)
+		return
+	}
+
+	if user.DeliveryMode != "" && !validDeliveryMode(user.DeliveryMode) {
+		http.Error(w, "Invalid delivery mode", http.StatusBadRequest)
+		return
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		http.Error(w, "Error hashing password", http.StatusInternalServerError)
@@ -61,8 +119,13 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// пользователь уже создан, поэтому ошибка отправки не отменяет регистрацию: письмо можно запросить повторно
+	if err := h.sendVerification(&user, 0); err != nil {
+		log.Printf("Error sending verification email to user ID %d: %v", user.ID, err)
+	}
+
 	w.WriteHeader(http.StatusCreated)
-	_, err = w.Write([]byte("User registered successfully"))
+	_, err = w.Write([]byte("User registered successfully. Check your email to verify the address"))
 	if err != nil {
 		log.Printf("Error writing response: %v", err)
 		return
@@ -94,44 +157,23 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokenString, err := h.tokenManager.GenerateJWT(dbUser.ID, h.JWTSecretKey)
+	// каждый вход начинает новую цепочку refresh-токенов
+	familyID, err := auth.NewFamilyID()
 	if err != nil {
-		http.Error(w, "Failed to generate JWT token", http.StatusInternalServerError)
+		log.Println("Error generating token family:", err)
+		http.Error(w, "Failed to generate refresh token", http.StatusInternalServerError)
 		return
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "jwt_token",
-		Value:    tokenString,
-		Expires:  time.Now().Add(24 * time.Hour),
-		Path:     "/",
-		SameSite: http.SameSiteStrictMode,
-		HttpOnly: true,
-		Secure:   true,
-	})
-
-	w.WriteHeader(http.StatusOK)
-	_, err = w.Write([]byte("Login successful"))
-	if err != nil {
-		log.Printf("Error writing response: %v", err)
-		return
-	}
+	h.issueTokens(w, dbUser.ID, familyID)
 }
 
 // Subscribe /api/subscribe
 func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
 	log.Println("Handling subscription request")
 
-	tokenStr := r.Header.Get("Authorization")
-	if tokenStr == "" {
-		http.Error(w, "Authorization header missing", http.StatusUnauthorized)
-		return
-	}
-
-	claims, err := h.tokenManager.ParseJWT(tokenStr, h.JWTSecretKey)
-	if err != nil {
-		log.Println("Error parsing JWT:", err)
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
@@ -149,11 +191,18 @@ func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
 		}
 	}(r.Body)
 
-	if err := h.subscriptionRepo.CreateSubscription(claims.UserID, reqBody.RelatedUserID); err != nil {
+	offsets, err := normalizeOffsets(reqBody.Offsets)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.subscriptionRepo.CreateSubscription(userID, reqBody.RelatedUserID, offsets); err != nil {
 		log.Println("Error creating subscription:", err)
 		http.Error(w, "Error creating subscription", http.StatusInternalServerError)
 		return
 	}
+	h.publishSubscriptionEvent(reqBody.RelatedUserID, userID, "subscribed")
 
 	log.Println("Subscription created successfully")
 	w.WriteHeader(http.StatusCreated)
@@ -169,26 +218,12 @@ func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetAvailableUsers(w http.ResponseWriter, r *http.Request) {
 	log.Println("Handling get available users request")
 
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "Authorization header missing", http.StatusUnauthorized)
-		return
-	}
-
-	claims, err := h.tokenManager.ParseJWT(authHeader, h.JWTSecretKey)
-	if err != nil {
-		log.Println("Error parsing JWT:", err)
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	if claims.UserID == 0 {
-		log.Println("Invalid user ID in token")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
-	users, err := h.userRepo.GetAvailableUsersForSubscription(claims.UserID)
+	users, err := h.userRepo.GetAvailableUsersForSubscription(userID)
 	if err != nil {
 		log.Println("Error fetching available users:", err)
 		http.Error(w, "Error fetching available users", http.StatusInternalServerError)
@@ -226,22 +261,8 @@ func (h *Handler) GetAvailableUsers(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
 	log.Println("Handling unsubscribe request")
 
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "Authorization header missing", http.StatusUnauthorized)
-		return
-	}
-
-	claims, err := h.tokenManager.ParseJWT(authHeader, h.JWTSecretKey)
-	if err != nil {
-		log.Println("Error parsing JWT:", err)
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	if claims.UserID == 0 {
-		log.Println("Invalid user ID in token")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
 		return
 	}
 
@@ -259,7 +280,7 @@ func (h *Handler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
 		}
 	}(r.Body)
 
-	err = h.subscriptionRepo.UnsubscribeUser(claims.UserID, reqBody.RelatedUserID)
+	err := h.subscriptionRepo.UnsubscribeUser(userID, reqBody.RelatedUserID)
 	if err != nil {
 		if err.Error() == "subscription does not exist" {
 			http.Error(w, "You are not subscribed to this user", http.StatusBadRequest)
@@ -269,6 +290,7 @@ func (h *Handler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+	h.publishSubscriptionEvent(reqBody.RelatedUserID, userID, "unsubscribed")
 
 	log.Println("Unsubscribed successfully")
 	w.WriteHeader(http.StatusOK)
@@ -6,11 +6,23 @@ import (
 	"birthdayReminder/internal/handler/login"
 	mock_handler "birthdayReminder/internal/handler/mocks"
 	"birthdayReminder/internal/handler/subscribe"
+	"birthdayReminder/internal/repository/announcement"
+	"birthdayReminder/internal/repository/inbox"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/push"
+	"birthdayReminder/internal/repository/refresh"
+	"birthdayReminder/internal/repository/subscription"
+	"birthdayReminder/internal/repository/telegram"
 	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/repository/webhook"
+	"birthdayReminder/internal/stream"
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"io/ioutil"
@@ -25,31 +37,69 @@ func TestRegister(t *testing.T) {
 	testCases := []struct {
 		name           string
 		payload        interface{}
-		setupMock      func(mockUserRepo *mock_handler.MockUserRepository)
+		setupMock      func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer)
 		expectedError  bool
 		expectedStatus int
 		expectedOutput string
 	}{
 		{
-			name:           "Invalid payload",
-			payload:        "invalid json",
-			setupMock:      func(mockUserRepo *mock_handler.MockUserRepository) {},
+			name:    "Invalid payload",
+			payload: "invalid json",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+			},
 			expectedError:  true,
 			expectedStatus: http.StatusBadRequest,
 			expectedOutput: "Invalid request payload",
 		},
 		{
-			name:           "Missing user data",
-			payload:        user.User{Name: "", Email: "", Password: "", DateOfBirth: time.Time{}},
-			setupMock:      func(mockUserRepo *mock_handler.MockUserRepository) {},
+			name:    "Missing user data",
+			payload: user.User{Name: "", Email: "", Password: "", DateOfBirth: time.Time{}},
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+			},
 			expectedError:  true,
 			expectedStatus: http.StatusBadRequest,
 			expectedOutput: "Invalid user data",
 		},
+		{
+			name:    "Email with display name",
+			payload: user.User{Name: "John Doe", Email: "John <john@example.com>", Password: "password", DateOfBirth: time.Now()},
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+			},
+			expectedError:  true,
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid email",
+		},
+		{
+			name:    "Email with header injection",
+			payload: user.User{Name: "John Doe", Email: "john@example.com\r\nBcc: victim@example.com", Password: "password", DateOfBirth: time.Now()},
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+			},
+			expectedError:  true,
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid email",
+		},
+		{
+			name:    "Malformed email",
+			payload: user.User{Name: "John Doe", Email: "not-an-email", Password: "password", DateOfBirth: time.Now()},
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+			},
+			expectedError:  true,
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid email",
+		},
+		{
+			name:    "Invalid time zone",
+			payload: user.User{Name: "John Doe", Email: "john@example.com", Password: "password", DateOfBirth: time.Now(), TimeZone: "Mars/Olympus"},
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+			},
+			expectedError:  true,
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid time zone",
+		},
 		{
 			name:    "Error saving user",
 			payload: user.User{Name: "John Doe", Email: "john@example.com", Password: "password", DateOfBirth: time.Now()},
-			setupMock: func(mockUserRepo *mock_handler.MockUserRepository) {
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
 				mockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
 			},
 			expectedError:  true,
@@ -59,8 +109,35 @@ func TestRegister(t *testing.T) {
 		{
 			name:    "Successful registration",
 			payload: user.User{Name: "John Doe", Email: "john@example.com", Password: "password", DateOfBirth: time.Now()},
-			setupMock: func(mockUserRepo *mock_handler.MockUserRepository) {
-				mockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil)
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+				mockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(func(u *user.User, hashedPassword []byte) error {
+					u.ID = 1
+					return nil
+				})
+				mockTokenManager.EXPECT().GenerateEmailVerificationToken(1, "john@example.com").Return("verify.token", "jti-1", nil)
+				mockUserRepo.EXPECT().StartVerification(1, "jti-1", time.Duration(0)).Return(true, nil)
+				mockMailer.EXPECT().SendVerification(gomock.Any(), "https://birthday.example.com/api/verify-email?token=verify.token").
+					DoAndReturn(func(recipient user.User, link string) error {
+						assert.Equal(t, "john@example.com", recipient.Email)
+						assert.False(t, recipient.EmailVerified)
+						return nil
+					})
+			},
+			expectedError:  false,
+			expectedStatus: http.StatusCreated,
+			expectedOutput: "User registered successfully",
+		},
+		{
+			name:    "Verification email failure does not fail registration",
+			payload: user.User{Name: "John Doe", Email: "john@example.com", Password: "password", DateOfBirth: time.Now()},
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+				mockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(func(u *user.User, hashedPassword []byte) error {
+					u.ID = 1
+					return nil
+				})
+				mockTokenManager.EXPECT().GenerateEmailVerificationToken(1, "john@example.com").Return("verify.token", "jti-1", nil)
+				mockUserRepo.EXPECT().StartVerification(1, "jti-1", time.Duration(0)).Return(true, nil)
+				mockMailer.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
 			},
 			expectedError:  false,
 			expectedStatus: http.StatusCreated,
@@ -73,8 +150,10 @@ func TestRegister(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
-			handler := &Handler{userRepo: mockUserRepo}
-			tt.setupMock(mockUserRepo)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			mockMailer := mock_handler.NewMockVerificationMailer(ctrl)
+			handler := &Handler{BaseURL: "https://birthday.example.com/", userRepo: mockUserRepo, mailer: mockMailer, tokenManager: mockTokenManager}
+			tt.setupMock(mockUserRepo, mockTokenManager, mockMailer)
 
 			reqBody, _ := json.Marshal(tt.payload)
 			req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(reqBody))
@@ -98,21 +177,22 @@ func TestLogin(t *testing.T) {
 	testCases := []struct {
 		name           string
 		payload        interface{}
-		setupMock      func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager)
+		setupMock      func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockRefreshRepo *mock_handler.MockRefreshTokenRepository)
 		expectedStatus int
 		expectedOutput string
 	}{
 		{
-			name:           "Invalid payload",
-			payload:        "invalid json",
-			setupMock:      func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {},
+			name:    "Invalid payload",
+			payload: "invalid json",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockRefreshRepo *mock_handler.MockRefreshTokenRepository) {
+			},
 			expectedStatus: http.StatusBadRequest,
 			expectedOutput: "Invalid request payload",
 		},
 		{
 			name:    "User not found",
 			payload: login.Dto{Email: "nonexistent@example.com", Password: "password"},
-			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockRefreshRepo *mock_handler.MockRefreshTokenRepository) {
 				mockUserRepo.EXPECT().GetUserByEmail("nonexistent@example.com").Return(nil, errors.New("user not found"))
 			},
 			expectedStatus: http.StatusUnauthorized,
@@ -121,8 +201,8 @@ func TestLogin(t *testing.T) {
 		{
 			name:    "Invalid password",
 			payload: login.Dto{Email: "john@example.com", Password: "wrongpassword"},
-			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				dbUser := &user.User{Email: "john@example.com", Password: "$2a$10$7wZT5nNPo8/W9cC5ZjJrBu57oG6e8CxPf49E7NmBztG7vZKbA35Oe"} // Пароль: password
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockRefreshRepo *mock_handler.MockRefreshTokenRepository) {
+				dbUser := &user.User{Email: "john@example.com", Password: "$2a$10$V4C8j6YJiUyoe1YTiGe31.DBcEOCl.jZNkWUrVjivwATDpxNFNr52"} // Пароль: password
 				mockUserRepo.EXPECT().GetUserByEmail("john@example.com").Return(dbUser, nil)
 			},
 			expectedStatus: http.StatusUnauthorized,
@@ -131,10 +211,10 @@ func TestLogin(t *testing.T) {
 		{
 			name:    "Failed to generate JWT token",
 			payload: login.Dto{Email: "john@example.com", Password: "password"},
-			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				dbUser := &user.User{Email: "john@example.com", Password: "$2a$10$7wZT5nNPo8/W9cC5ZjJrBu57oG6e8CxPf49E7NmBztG7vZKbA35Oe"} // Пароль: password
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockRefreshRepo *mock_handler.MockRefreshTokenRepository) {
+				dbUser := &user.User{Email: "john@example.com", Password: "$2a$10$V4C8j6YJiUyoe1YTiGe31.DBcEOCl.jZNkWUrVjivwATDpxNFNr52"} // Пароль: password
 				mockUserRepo.EXPECT().GetUserByEmail("john@example.com").Return(dbUser, nil)
-				mockTokenManager.EXPECT().GenerateJWT(dbUser.ID, gomock.Any()).Return("", errors.New("failed to generate JWT token"))
+				mockTokenManager.EXPECT().GenerateJWT(dbUser.ID).Return("", errors.New("failed to generate JWT token"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedOutput: "Failed to generate JWT token",
@@ -142,13 +222,18 @@ func TestLogin(t *testing.T) {
 		{
 			name:    "Successful login",
 			payload: login.Dto{Email: "john@example.com", Password: "password"},
-			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				dbUser := &user.User{Email: "john@example.com", Password: "$2a$10$7wZT5nNPo8/W9cC5ZjJrBu57oG6e8CxPf49E7NmBztG7vZKbA35Oe"} // Пароль: password
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockRefreshRepo *mock_handler.MockRefreshTokenRepository) {
+				dbUser := &user.User{Email: "john@example.com", Password: "$2a$10$V4C8j6YJiUyoe1YTiGe31.DBcEOCl.jZNkWUrVjivwATDpxNFNr52"} // Пароль: password
 				mockUserRepo.EXPECT().GetUserByEmail("john@example.com").Return(dbUser, nil)
-				mockTokenManager.EXPECT().GenerateJWT(dbUser.ID, gomock.Any()).Return("mock_jwt_token", nil)
+				mockTokenManager.EXPECT().GenerateJWT(dbUser.ID).Return("mock_jwt_token", nil)
+				mockRefreshRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *refresh.Token) error {
+					assert.Len(t, token.TokenHash, 64)
+					assert.NotEmpty(t, token.FamilyID)
+					return nil
+				})
 			},
 			expectedStatus: http.StatusOK,
-			expectedOutput: "Login successful",
+			expectedOutput: `"access_token":"mock_jwt_token"`,
 		},
 	}
 
@@ -159,9 +244,10 @@ func TestLogin(t *testing.T) {
 
 			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
 			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
-			handler := &Handler{userRepo: mockUserRepo, tokenManager: mockTokenManager, JWTSecretKey: "secret"}
+			mockRefreshRepo := mock_handler.NewMockRefreshTokenRepository(ctrl)
+			handler := &Handler{userRepo: mockUserRepo, refreshRepo: mockRefreshRepo, tokenManager: mockTokenManager}
 
-			tt.setupMock(mockUserRepo, mockTokenManager)
+			tt.setupMock(mockUserRepo, mockTokenManager, mockRefreshRepo)
 
 			reqBody, err := json.Marshal(tt.payload)
 			if err != nil {
@@ -188,65 +274,87 @@ func TestLogin(t *testing.T) {
 	}
 }
 
-func TestSubscribe(t *testing.T) {
+func TestRefreshToken(t *testing.T) {
+	const refreshToken = "old-refresh-token"
+	hash := auth.HashRefreshToken(refreshToken)
+	usedAt := time.Now().Add(-time.Hour)
+	stored := func() *refresh.Token {
+		return &refresh.Token{ID: 4, UserID: 1, FamilyID: "family", TokenHash: hash, ExpiresAt: time.Now().Add(time.Hour)}
+	}
+
 	testCases := []struct {
 		name           string
-		token          string
-		payload        interface{}
-		setupMock      func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager)
+		payload        string
+		cookie         string
+		setupMock      func(mockRefreshRepo *mock_handler.MockRefreshTokenRepository, mockTokenManager *mock_auth.MockTokenManager)
 		expectedStatus int
 		expectedOutput string
 	}{
 		{
-			name:    "Missing Authorization header",
-			token:   "",
-			payload: subscribe.RequestDto{RelatedUserID: 2},
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+			name:           "No refresh token",
+			setupMock:      func(*mock_handler.MockRefreshTokenRepository, *mock_auth.MockTokenManager) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Refresh token missing",
+		},
+		{
+			name:    "Unknown token",
+			payload: `{"refresh_token": "forged"}`,
+			setupMock: func(mockRefreshRepo *mock_handler.MockRefreshTokenRepository, _ *mock_auth.MockTokenManager) {
+				mockRefreshRepo.EXPECT().GetByHash(auth.HashRefreshToken("forged")).Return(nil, refresh.ErrNotFound)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedOutput: "Authorization header missing",
+			expectedOutput: "Invalid refresh token",
 		},
 		{
-			name:    "Invalid JWT token",
-			token:   "invalid_token",
-			payload: subscribe.RequestDto{RelatedUserID: 2},
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("invalid_token", "secret").Return(nil, errors.New("invalid token"))
+			name:    "Expired token",
+			payload: `{"refresh_token": "` + refreshToken + `"}`,
+			setupMock: func(mockRefreshRepo *mock_handler.MockRefreshTokenRepository, _ *mock_auth.MockTokenManager) {
+				expired := stored()
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				mockRefreshRepo.EXPECT().GetByHash(hash).Return(expired, nil)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedOutput: "Unauthorized",
+			expectedOutput: "Invalid refresh token",
 		},
 		{
-			name:    "Error decoding request body",
-			token:   "valid_token",
-			payload: "invalid_json",
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("valid_token", "secret").Return(&auth.Claims{UserID: 1}, nil)
+			name:    "Replayed token revokes the family",
+			payload: `{"refresh_token": "` + refreshToken + `"}`,
+			setupMock: func(mockRefreshRepo *mock_handler.MockRefreshTokenRepository, _ *mock_auth.MockTokenManager) {
+				replayed := stored()
+				replayed.UsedAt = &usedAt
+				mockRefreshRepo.EXPECT().GetByHash(hash).Return(replayed, nil)
+				mockRefreshRepo.EXPECT().RevokeFamily("family").Return(nil)
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedOutput: "Invalid request payload",
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Invalid refresh token",
 		},
 		{
-			name:    "Error creating subscription",
-			token:   "valid_token",
-			payload: subscribe.RequestDto{RelatedUserID: 2},
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("valid_token", "secret").Return(&auth.Claims{UserID: 1}, nil)
-				mockSubscriptionRepo.EXPECT().CreateSubscription(1, 2).Return(errors.New("error creating subscription"))
+			name:    "Concurrent rotation revokes the family",
+			payload: `{"refresh_token": "` + refreshToken + `"}`,
+			setupMock: func(mockRefreshRepo *mock_handler.MockRefreshTokenRepository, _ *mock_auth.MockTokenManager) {
+				mockRefreshRepo.EXPECT().GetByHash(hash).Return(stored(), nil)
+				mockRefreshRepo.EXPECT().MarkUsed(4).Return(false, nil)
+				mockRefreshRepo.EXPECT().RevokeFamily("family").Return(nil)
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedOutput: "Error creating subscription",
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Invalid refresh token",
 		},
 		{
-			name:    "Successful subscription creation",
-			token:   "valid_token",
-			payload: subscribe.RequestDto{RelatedUserID: 2},
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("valid_token", "secret").Return(&auth.Claims{UserID: 1}, nil)
-				mockSubscriptionRepo.EXPECT().CreateSubscription(1, 2).Return(nil)
+			name:   "Rotation from cookie",
+			cookie: refreshToken,
+			setupMock: func(mockRefreshRepo *mock_handler.MockRefreshTokenRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockRefreshRepo.EXPECT().GetByHash(hash).Return(stored(), nil)
+				mockRefreshRepo.EXPECT().MarkUsed(4).Return(true, nil)
+				mockTokenManager.EXPECT().GenerateJWT(1).Return("new.jwt", nil)
+				mockRefreshRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *refresh.Token) error {
+					assert.Equal(t, 1, token.UserID)
+					assert.Equal(t, "family", token.FamilyID)
+					assert.NotEqual(t, hash, token.TokenHash)
+					return nil
+				})
 			},
-			expectedStatus: http.StatusCreated,
-			expectedOutput: "Subscription created successfully",
+			expectedStatus: http.StatusOK,
+			expectedOutput: `"access_token":"new.jwt"`,
 		},
 	}
 
@@ -255,92 +363,75 @@ func TestSubscribe(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			mockSubscriptionRepo := mock_handler.NewMockSubscriptionRepository(ctrl)
+			mockRefreshRepo := mock_handler.NewMockRefreshTokenRepository(ctrl)
 			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{refreshRepo: mockRefreshRepo, tokenManager: mockTokenManager}
+			tt.setupMock(mockRefreshRepo, mockTokenManager)
 
-			handler := &Handler{
-				subscriptionRepo: mockSubscriptionRepo,
-				tokenManager:     mockTokenManager,
-				JWTSecretKey:     "secret",
-			}
-
-			tt.setupMock(mockSubscriptionRepo, mockTokenManager)
-
-			reqBody, err := json.Marshal(tt.payload)
-			if err != nil {
-				t.Fatalf("Failed to marshal request body: %v", err)
+			req := httptest.NewRequest(http.MethodPost, "/api/token/refresh", strings.NewReader(tt.payload))
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "refresh_token", Value: tt.cookie})
 			}
-
-			req := httptest.NewRequest(http.MethodPost, "/subscribe", bytes.NewBuffer(reqBody))
-			req.Header.Set("Authorization", tt.token)
-			req.Header.Set("Content-Type", "application/json")
-
 			w := httptest.NewRecorder()
 
-			handler.Subscribe(w, req)
+			handler.RefreshToken(w, req)
 
 			res := w.Result()
 			defer res.Body.Close()
 
-			if res.StatusCode != tt.expectedStatus {
-				t.Errorf("Expected status %d; got %d", tt.expectedStatus, res.StatusCode)
-			}
-
-			body, err := ioutil.ReadAll(res.Body)
-			if err != nil {
-				t.Fatalf("Failed to read response body: %v", err)
-			}
-
-			if !strings.Contains(string(body), tt.expectedOutput) {
-				t.Errorf("Expected response body to contain '%s'; got '%s'", tt.expectedOutput, string(body))
-			}
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
 		})
 	}
 }
 
-func TestGetAvailableUsers(t *testing.T) {
+func TestLogout(t *testing.T) {
+	claims := &auth.Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{ID: "jti"}}
+
 	testCases := []struct {
 		name           string
-		token          string
-		setupMock      func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager)
+		cookie         string
+		setupMock      func(mockRefreshRepo *mock_handler.MockRefreshTokenRepository, mockTokenManager *mock_auth.MockTokenManager)
 		expectedStatus int
 		expectedOutput string
 	}{
 		{
-			name:  "Missing Authorization header",
-			token: "",
-			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+			name: "Revocation fails",
+			setupMock: func(_ *mock_handler.MockRefreshTokenRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().RevokeJWT(claims).Return(errors.New("db down"))
 			},
-			expectedStatus: http.StatusUnauthorized,
-			expectedOutput: "Authorization header missing",
+			expectedStatus: http.StatusInternalServerError,
+			expectedOutput: "Error logging out",
 		},
 		{
-			name:  "Invalid JWT token",
-			token: "invalid.token",
-			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("invalid.token", "secret").Return(nil, errors.New("invalid token"))
+			name: "Access token only",
+			setupMock: func(_ *mock_handler.MockRefreshTokenRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().RevokeJWT(claims).Return(nil)
 			},
-			expectedStatus: http.StatusUnauthorized,
-			expectedOutput: "Unauthorized",
+			expectedStatus: http.StatusOK,
+			expectedOutput: "Logged out",
 		},
 		{
-			name:  "Invalid user ID in token",
-			token: "valid.token.with.invalid.userID",
-			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("valid.token.with.invalid.userID", "secret").Return(&auth.Claims{UserID: 0}, nil)
+			name:   "Refresh token family is revoked",
+			cookie: "refresh",
+			setupMock: func(mockRefreshRepo *mock_handler.MockRefreshTokenRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().RevokeJWT(claims).Return(nil)
+				mockRefreshRepo.EXPECT().GetByHash(auth.HashRefreshToken("refresh")).Return(&refresh.Token{UserID: 1, FamilyID: "family"}, nil)
+				mockRefreshRepo.EXPECT().RevokeFamily("family").Return(nil)
 			},
-			expectedStatus: http.StatusUnauthorized,
-			expectedOutput: "Unauthorized",
+			expectedStatus: http.StatusOK,
+			expectedOutput: "Logged out",
 		},
 		{
-			name:  "Error fetching available users",
-			token: "valid.token",
-			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("valid.token", "secret").Return(&auth.Claims{UserID: 1}, nil)
-				mockUserRepo.EXPECT().GetAvailableUsersForSubscription(1).Return(nil, errors.New("database error"))
+			name:   "Refresh token of another user is ignored",
+			cookie: "foreign",
+			setupMock: func(mockRefreshRepo *mock_handler.MockRefreshTokenRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().RevokeJWT(claims).Return(nil)
+				mockRefreshRepo.EXPECT().GetByHash(auth.HashRefreshToken("foreign")).Return(&refresh.Token{UserID: 2, FamilyID: "other"}, nil)
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedOutput: "Error fetching available users",
+			expectedStatus: http.StatusOK,
+			expectedOutput: "Logged out",
 		},
 	}
 
@@ -349,110 +440,162 @@ func TestGetAvailableUsers(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockRefreshRepo := mock_handler.NewMockRefreshTokenRepository(ctrl)
 			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
-			handler := &Handler{
-				JWTSecretKey:     "secret",
-				userRepo:         mockUserRepo,
-				subscriptionRepo: nil,
-				tokenManager:     mockTokenManager,
-			}
+			handler := &Handler{refreshRepo: mockRefreshRepo, tokenManager: mockTokenManager}
 
-			tt.setupMock(mockUserRepo, mockTokenManager)
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(claims, nil)
+			tt.setupMock(mockRefreshRepo, mockTokenManager)
 
-			req := httptest.NewRequest(http.MethodGet, "/users/available", nil)
-			req.Header.Set("Authorization", tt.token)
+			req := httptest.NewRequest(http.MethodPost, "/api/logout", nil)
+			req.Header.Set("Authorization", "Bearer valid.token")
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "refresh_token", Value: tt.cookie})
+			}
 			w := httptest.NewRecorder()
 
-			handler.GetAvailableUsers(w, req)
+			handler.Authenticate(http.HandlerFunc(handler.Logout)).ServeHTTP(w, req)
 
 			res := w.Result()
 			defer res.Body.Close()
 
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
-
 			body, _ := io.ReadAll(res.Body)
 			assert.Contains(t, string(body), tt.expectedOutput)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Len(t, res.Cookies(), 2)
+				for _, cookie := range res.Cookies() {
+					assert.Equal(t, -1, cookie.MaxAge)
+				}
+			}
 		})
 	}
 }
 
-func TestUnsubscribe(t *testing.T) {
+func TestLogoutAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshRepo := mock_handler.NewMockRefreshTokenRepository(ctrl)
+	mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+	handler := &Handler{refreshRepo: mockRefreshRepo, tokenManager: mockTokenManager}
+
+	claims := &auth.Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{ID: "abc"}}
+	mockTokenManager.EXPECT().ParseJWT("valid.token").Return(claims, nil)
+	mockTokenManager.EXPECT().RevokeJWT(claims).Return(nil)
+	mockTokenManager.EXPECT().RevokeAllJWT(1).Return(nil)
+	mockRefreshRepo.EXPECT().RevokeUser(1).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/logout-all", nil)
+	req.Header.Set("Authorization", "Bearer valid.token")
+	w := httptest.NewRecorder()
+
+	handler.Authenticate(http.HandlerFunc(handler.LogoutAll)).ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "Logged out on all devices", string(body))
+}
+
+func TestGetJWKS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+	handler := &Handler{tokenManager: mockTokenManager}
+
+	mockTokenManager.EXPECT().JWKS().Return(auth.JWKSet{Keys: []auth.JWK{
+		{KeyType: "OKP", KeyID: "current", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetJWKS(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	body, _ := io.ReadAll(res.Body)
+	assert.JSONEq(t, `{"keys": [{"kty": "OKP", "kid": "current", "use": "sig", "alg": "EdDSA", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`, string(body))
+}
+
+func TestVerifyEmail(t *testing.T) {
+	claims := &auth.EmailVerificationClaims{
+		Email:            "john@example.com",
+		RegisteredClaims: jwt.RegisteredClaims{ID: "jti-1", Subject: "1"},
+	}
+
 	testCases := []struct {
 		name           string
 		token          string
-		requestBody    subscribe.RequestDto
-		setupMock      func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager)
+		setupMock      func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager)
 		expectedStatus int
 		expectedOutput string
 	}{
 		{
-			name:        "Missing Authorization header",
-			token:       "",
-			requestBody: subscribe.RequestDto{},
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+			name:  "Missing token",
+			token: "",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
 			},
-			expectedStatus: http.StatusUnauthorized,
-			expectedOutput: "Authorization header missing\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Verification token missing",
 		},
 		{
-			name:        "Invalid JWT token",
-			token:       "invalid.token",
-			requestBody: subscribe.RequestDto{},
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("invalid.token", "secret").Return(nil, errors.New("invalid token"))
+			name:  "Invalid or expired token",
+			token: "expired.token",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseEmailVerificationToken("expired.token").Return(nil, errors.New("invalid token"))
 			},
-			expectedStatus: http.StatusUnauthorized,
-			expectedOutput: "Unauthorized\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid or expired verification link",
 		},
 		{
-			name:        "Invalid user ID in token",
-			token:       "valid.token.with.invalid.userID",
-			requestBody: subscribe.RequestDto{},
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("valid.token.with.invalid.userID", "secret").Return(&auth.Claims{UserID: 0}, nil)
+			name:  "Verified",
+			token: "verify.token",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseEmailVerificationToken("verify.token").Return(claims, nil)
+				mockUserRepo.EXPECT().VerifyEmail(1, "john@example.com", "jti-1").Return(true, nil)
 			},
-			expectedStatus: http.StatusUnauthorized,
-			expectedOutput: "Unauthorized\n",
+			expectedStatus: http.StatusOK,
+			expectedOutput: "Email verified successfully",
 		},
 		{
-			name:  "Subscription does not exist",
-			token: "valid.token",
-			requestBody: subscribe.RequestDto{
-				RelatedUserID: 2,
-			},
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("valid.token", "secret").Return(&auth.Claims{UserID: 1}, nil)
-				mockSubscriptionRepo.EXPECT().UnsubscribeUser(1, 2).Return(errors.New("subscription does not exist"))
+			name:  "Link already used",
+			token: "verify.token",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseEmailVerificationToken("verify.token").Return(claims, nil)
+				mockUserRepo.EXPECT().VerifyEmail(1, "john@example.com", "jti-1").Return(false, nil)
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, Email: "john@example.com", EmailVerified: true}, nil)
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedOutput: "You are not subscribed to this user\n",
+			expectedStatus: http.StatusOK,
+			expectedOutput: "Email already verified",
 		},
 		{
-			name:  "Error unsubscribing user",
-			token: "valid.token",
-			requestBody: subscribe.RequestDto{
-				RelatedUserID: 2,
-			},
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("valid.token", "secret").Return(&auth.Claims{UserID: 1}, nil)
-				mockSubscriptionRepo.EXPECT().UnsubscribeUser(1, 2).Return(errors.New("internal error"))
+			name:  "Link replaced by a newer one",
+			token: "verify.token",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseEmailVerificationToken("verify.token").Return(claims, nil)
+				mockUserRepo.EXPECT().VerifyEmail(1, "john@example.com", "jti-1").Return(false, nil)
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, Email: "john@example.com"}, nil)
 			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedOutput: "Error unsubscribing user\n",
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid or expired verification link",
 		},
 		{
-			name:  "Successful unsubscription",
-			token: "valid.token",
-			requestBody: subscribe.RequestDto{
-				RelatedUserID: 2,
-			},
-			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
-				mockTokenManager.EXPECT().ParseJWT("valid.token", "secret").Return(&auth.Claims{UserID: 1}, nil)
-				mockSubscriptionRepo.EXPECT().UnsubscribeUser(1, 2).Return(nil)
+			name:  "Database error",
+			token: "verify.token",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseEmailVerificationToken("verify.token").Return(claims, nil)
+				mockUserRepo.EXPECT().VerifyEmail(1, "john@example.com", "jti-1").Return(false, errors.New("db down"))
 			},
-			expectedStatus: http.StatusOK,
-			expectedOutput: "Unsubscribed successfully",
+			expectedStatus: http.StatusInternalServerError,
+			expectedOutput: "Error verifying email",
 		},
 	}
 
@@ -461,30 +604,1669 @@ func TestUnsubscribe(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			mockSubscriptionRepo := mock_handler.NewMockSubscriptionRepository(ctrl)
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
 			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
-			handler := &Handler{
-				JWTSecretKey:     "secret",
-				subscriptionRepo: mockSubscriptionRepo,
-				tokenManager:     mockTokenManager,
-			}
-
-			tt.setupMock(mockSubscriptionRepo, mockTokenManager)
+			handler := &Handler{userRepo: mockUserRepo, tokenManager: mockTokenManager}
+			tt.setupMock(mockUserRepo, mockTokenManager)
 
-			body, _ := json.Marshal(tt.requestBody)
-			req := httptest.NewRequest(http.MethodPost, "/unsubscribe", bytes.NewBuffer(body))
-			req.Header.Set("Authorization", tt.token)
+			req := httptest.NewRequest(http.MethodGet, "/api/verify-email?token="+tt.token, nil)
 			w := httptest.NewRecorder()
 
-			handler.Unsubscribe(w, req)
+			handler.VerifyEmail(w, req)
 
 			res := w.Result()
 			defer res.Body.Close()
 
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
 
-			responseBody, _ := io.ReadAll(res.Body)
-			assert.Equal(t, tt.expectedOutput, string(responseBody))
+func TestResendVerification(t *testing.T) {
+	testCases := []struct {
+		name               string
+		setupMock          func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer)
+		expectedStatus     int
+		expectedOutput     string
+		expectedRetryAfter string
+	}{
+		{
+			name: "Already verified",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, Email: "john@example.com", EmailVerified: true}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedOutput: "Email already verified",
+		},
+		{
+			name: "Sent too recently",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, Email: "john@example.com"}, nil)
+				mockTokenManager.EXPECT().GenerateEmailVerificationToken(1, "john@example.com").Return("verify.token", "jti-2", nil)
+				mockUserRepo.EXPECT().StartVerification(1, "jti-2", verificationResendInterval).Return(false, nil)
+			},
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedOutput:     "Verification email was sent recently",
+			expectedRetryAfter: "300",
+		},
+		{
+			name: "Mail error",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, Email: "john@example.com"}, nil)
+				mockTokenManager.EXPECT().GenerateEmailVerificationToken(1, "john@example.com").Return("verify.token", "jti-2", nil)
+				mockUserRepo.EXPECT().StartVerification(1, "jti-2", verificationResendInterval).Return(true, nil)
+				mockMailer.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedOutput: "Error sending verification email",
+		},
+		{
+			name: "Sent",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager, mockMailer *mock_handler.MockVerificationMailer) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, Email: "john@example.com"}, nil)
+				mockTokenManager.EXPECT().GenerateEmailVerificationToken(1, "john@example.com").Return("verify.token", "jti-2", nil)
+				mockUserRepo.EXPECT().StartVerification(1, "jti-2", verificationResendInterval).Return(true, nil)
+				mockMailer.EXPECT().SendVerification(gomock.Any(), "http://localhost:8080/api/verify-email?token=verify.token").Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedOutput: "Verification email sent",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			mockMailer := mock_handler.NewMockVerificationMailer(ctrl)
+			handler := &Handler{BaseURL: defaultBaseURL, userRepo: mockUserRepo, mailer: mockMailer, tokenManager: mockTokenManager}
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			tt.setupMock(mockUserRepo, mockTokenManager, mockMailer)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/verify-email/resend", nil)
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.ResendVerification)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			assert.Equal(t, tt.expectedRetryAfter, res.Header.Get("Retry-After"))
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		header         string
+		cookie         string
+		setupMock      func(mockTokenManager *mock_auth.MockTokenManager)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:           "No token",
+			setupMock:      func(mockTokenManager *mock_auth.MockTokenManager) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Authorization header missing",
+		},
+		{
+			name:           "Token without scheme",
+			header:         "valid.token",
+			setupMock:      func(mockTokenManager *mock_auth.MockTokenManager) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Invalid Authorization header",
+		},
+		{
+			name:   "Bearer header",
+			header: "bearer valid.token",
+			setupMock: func(mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 7}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: "user 7",
+		},
+		{
+			name:   "Cookie",
+			cookie: "cookie.token",
+			setupMock: func(mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("cookie.token").Return(&auth.Claims{UserID: 7}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: "user 7",
+		},
+		{
+			name:   "Header takes precedence over cookie",
+			header: "Bearer header.token",
+			cookie: "cookie.token",
+			setupMock: func(mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("header.token").Return(&auth.Claims{UserID: 8}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: "user 8",
+		},
+		{
+			name:   "Invalid token",
+			header: "Bearer expired.token",
+			setupMock: func(mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("expired.token").Return(nil, errors.New("token is expired"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Unauthorized",
+		},
+		{
+			name:   "Token without user",
+			header: "Bearer anonymous.token",
+			setupMock: func(mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("anonymous.token").Return(&auth.Claims{}, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Unauthorized",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{tokenManager: mockTokenManager}
+			tt.setupMock(mockTokenManager)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID, ok := userIDFromContext(r.Context())
+				assert.True(t, ok)
+				fmt.Fprintf(w, "user %d", userID)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "jwt_token", Value: tt.cookie})
+			}
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(next).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	testCases := []struct {
+		name           string
+		token          string
+		payload        interface{}
+		setupMock      func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:    "Missing Authorization header",
+			token:   "",
+			payload: subscribe.RequestDto{RelatedUserID: 2},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Authorization header missing",
+		},
+		{
+			name:    "Invalid JWT token",
+			token:   "invalid_token",
+			payload: subscribe.RequestDto{RelatedUserID: 2},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("invalid_token").Return(nil, errors.New("invalid token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Unauthorized",
+		},
+		{
+			name:    "Error decoding request body",
+			token:   "valid_token",
+			payload: "invalid_json",
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid_token").Return(&auth.Claims{UserID: 1}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid request payload",
+		},
+		{
+			name:    "Error creating subscription",
+			token:   "valid_token",
+			payload: subscribe.RequestDto{RelatedUserID: 2},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid_token").Return(&auth.Claims{UserID: 1}, nil)
+				mockSubscriptionRepo.EXPECT().CreateSubscription(1, 2, []int{1}).Return(errors.New("error creating subscription"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedOutput: "Error creating subscription",
+		},
+		{
+			name:    "Successful subscription creation",
+			token:   "valid_token",
+			payload: subscribe.RequestDto{RelatedUserID: 2},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid_token").Return(&auth.Claims{UserID: 1}, nil)
+				mockSubscriptionRepo.EXPECT().CreateSubscription(1, 2, []int{1}).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedOutput: "Subscription created successfully",
+		},
+		{
+			name:    "Invalid offsets",
+			token:   "valid_token",
+			payload: subscribe.RequestDto{RelatedUserID: 2, Offsets: []int{1, -3}},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid_token").Return(&auth.Claims{UserID: 1}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "offsets must be between 0 and 60 days",
+		},
+		{
+			name:    "Subscription with several offsets",
+			token:   "valid_token",
+			payload: subscribe.RequestDto{RelatedUserID: 2, Offsets: []int{0, 7, 1, 7}},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid_token").Return(&auth.Claims{UserID: 1}, nil)
+				mockSubscriptionRepo.EXPECT().CreateSubscription(1, 2, []int{7, 1, 0}).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedOutput: "Subscription created successfully",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSubscriptionRepo := mock_handler.NewMockSubscriptionRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+
+			handler := &Handler{
+				subscriptionRepo: mockSubscriptionRepo,
+				tokenManager:     mockTokenManager,
+			}
+
+			tt.setupMock(mockSubscriptionRepo, mockTokenManager)
+
+			reqBody, err := json.Marshal(tt.payload)
+			if err != nil {
+				t.Fatalf("Failed to marshal request body: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/subscribe", bytes.NewBuffer(reqBody))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.Subscribe)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d; got %d", tt.expectedStatus, res.StatusCode)
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("Failed to read response body: %v", err)
+			}
+
+			if !strings.Contains(string(body), tt.expectedOutput) {
+				t.Errorf("Expected response body to contain '%s'; got '%s'", tt.expectedOutput, string(body))
+			}
+		})
+	}
+}
+
+func TestGetAvailableUsers(t *testing.T) {
+	testCases := []struct {
+		name           string
+		token          string
+		setupMock      func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:  "Missing Authorization header",
+			token: "",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Authorization header missing",
+		},
+		{
+			name:  "Invalid JWT token",
+			token: "invalid.token",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("invalid.token").Return(nil, errors.New("invalid token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Unauthorized",
+		},
+		{
+			name:  "Invalid user ID in token",
+			token: "valid.token.with.invalid.userID",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token.with.invalid.userID").Return(&auth.Claims{UserID: 0}, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Unauthorized",
+		},
+		{
+			name:  "Error fetching available users",
+			token: "valid.token",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+				mockUserRepo.EXPECT().GetAvailableUsersForSubscription(1).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedOutput: "Error fetching available users",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				userRepo:         mockUserRepo,
+				subscriptionRepo: nil,
+				tokenManager:     mockTokenManager,
+			}
+
+			tt.setupMock(mockUserRepo, mockTokenManager)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/available", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.GetAvailableUsers)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	testCases := []struct {
+		name           string
+		token          string
+		requestBody    subscribe.RequestDto
+		setupMock      func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:        "Missing Authorization header",
+			token:       "",
+			requestBody: subscribe.RequestDto{},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Authorization header missing\n",
+		},
+		{
+			name:        "Invalid JWT token",
+			token:       "invalid.token",
+			requestBody: subscribe.RequestDto{},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("invalid.token").Return(nil, errors.New("invalid token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Unauthorized\n",
+		},
+		{
+			name:        "Invalid user ID in token",
+			token:       "valid.token.with.invalid.userID",
+			requestBody: subscribe.RequestDto{},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token.with.invalid.userID").Return(&auth.Claims{UserID: 0}, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Unauthorized\n",
+		},
+		{
+			name:  "Subscription does not exist",
+			token: "valid.token",
+			requestBody: subscribe.RequestDto{
+				RelatedUserID: 2,
+			},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+				mockSubscriptionRepo.EXPECT().UnsubscribeUser(1, 2).Return(errors.New("subscription does not exist"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "You are not subscribed to this user\n",
+		},
+		{
+			name:  "Error unsubscribing user",
+			token: "valid.token",
+			requestBody: subscribe.RequestDto{
+				RelatedUserID: 2,
+			},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+				mockSubscriptionRepo.EXPECT().UnsubscribeUser(1, 2).Return(errors.New("internal error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedOutput: "Error unsubscribing user\n",
+		},
+		{
+			name:  "Successful unsubscription",
+			token: "valid.token",
+			requestBody: subscribe.RequestDto{
+				RelatedUserID: 2,
+			},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+				mockSubscriptionRepo.EXPECT().UnsubscribeUser(1, 2).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: "Unsubscribed successfully",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSubscriptionRepo := mock_handler.NewMockSubscriptionRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				subscriptionRepo: mockSubscriptionRepo,
+				tokenManager:     mockTokenManager,
+			}
+
+			tt.setupMock(mockSubscriptionRepo, mockTokenManager)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/unsubscribe", bytes.NewBuffer(body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.Unsubscribe)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			responseBody, _ := io.ReadAll(res.Body)
+			assert.Equal(t, tt.expectedOutput, string(responseBody))
+		})
+	}
+}
+
+func TestRetryNotification(t *testing.T) {
+	testCases := []struct {
+		name           string
+		id             string
+		setupMock      func(mockUserRepo *mock_handler.MockUserRepository, mockNotificationRepo *mock_handler.MockNotificationRepository, mockTokenManager *mock_auth.MockTokenManager)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name: "Not an admin",
+			id:   "5",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockNotificationRepo *mock_handler.MockNotificationRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedOutput: "Forbidden",
+		},
+		{
+			name: "Dead notification not found",
+			id:   "5",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockNotificationRepo *mock_handler.MockNotificationRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, IsAdmin: true}, nil)
+				mockNotificationRepo.EXPECT().Requeue(5).Return(notification.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedOutput: "Dead notification not found",
+		},
+		{
+			name: "Successful requeue",
+			id:   "5",
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockNotificationRepo *mock_handler.MockNotificationRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, IsAdmin: true}, nil)
+				mockNotificationRepo.EXPECT().Requeue(5).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: "Notification queued for retry",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockNotificationRepo := mock_handler.NewMockNotificationRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				userRepo:         mockUserRepo,
+				notificationRepo: mockNotificationRepo,
+				tokenManager:     mockTokenManager,
+			}
+
+			tt.setupMock(mockUserRepo, mockNotificationRepo, mockTokenManager)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/notifications/"+tt.id+"/retry", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.RetryNotification)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestUpdateSubscription(t *testing.T) {
+	testCases := []struct {
+		name           string
+		id             string
+		payload        interface{}
+		setupMock      func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:           "Too many offsets",
+			id:             "3",
+			payload:        subscribe.UpdateRequestDto{Offsets: []int{0, 1, 2, 3, 4, 5}},
+			setupMock:      func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "at most 5 offsets are allowed",
+		},
+		{
+			name:    "Subscription of another user",
+			id:      "3",
+			payload: subscribe.UpdateRequestDto{Offsets: []int{7}},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository) {
+				mockSubscriptionRepo.EXPECT().UpdateOffsets(3, 1, []int{7}).Return(subscription.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedOutput: "Subscription not found",
+		},
+		{
+			name:    "Successful update",
+			id:      "3",
+			payload: subscribe.UpdateRequestDto{Offsets: []int{1, 7}},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository) {
+				mockSubscriptionRepo.EXPECT().UpdateOffsets(3, 1, []int{7, 1}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: "Subscription updated successfully",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSubscriptionRepo := mock_handler.NewMockSubscriptionRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				subscriptionRepo: mockSubscriptionRepo,
+				tokenManager:     mockTokenManager,
+			}
+
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			tt.setupMock(mockSubscriptionRepo)
+
+			body, _ := json.Marshal(tt.payload)
+			req := httptest.NewRequest(http.MethodPut, "/api/subscriptions/"+tt.id, bytes.NewBuffer(body))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.UpdateSubscription)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			responseBody, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(responseBody), tt.expectedOutput)
+		})
+	}
+}
+
+func TestSnoozeSubscription(t *testing.T) {
+	today := time.Now().UTC()
+	inTenDays := today.AddDate(0, 0, 10).Format("2006-01-02")
+	celebrant := &user.User{ID: 5, DateOfBirth: today.AddDate(-30, 0, 3)}
+	nextBirthday := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 3)
+
+	testCases := []struct {
+		name           string
+		payload        interface{}
+		setupMock      func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockUserRepo *mock_handler.MockUserRepository)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:           "Neither until nor skip_next",
+			payload:        subscribe.SnoozeRequestDto{},
+			setupMock:      func(*mock_handler.MockSubscriptionRepository, *mock_handler.MockUserRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Specify either until or skip_next",
+		},
+		{
+			name:    "Subscription of another user",
+			payload: subscribe.SnoozeRequestDto{SkipNext: true},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, _ *mock_handler.MockUserRepository) {
+				mockSubscriptionRepo.EXPECT().GetSubscription(3, 1).Return(nil, subscription.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedOutput: "Subscription not found",
+		},
+		{
+			name:    "Until in the past",
+			payload: subscribe.SnoozeRequestDto{Until: "2020-01-01"},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, _ *mock_handler.MockUserRepository) {
+				mockSubscriptionRepo.EXPECT().GetSubscription(3, 1).Return(&subscription.Subscription{ID: 3, UserID: 1, RelatedUserID: 5}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "until must be in the future",
+		},
+		{
+			name:    "Snooze until date",
+			payload: subscribe.SnoozeRequestDto{Until: inTenDays},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, _ *mock_handler.MockUserRepository) {
+				mockSubscriptionRepo.EXPECT().GetSubscription(3, 1).Return(&subscription.Subscription{ID: 3, UserID: 1, RelatedUserID: 5}, nil)
+				mockSubscriptionRepo.EXPECT().Snooze(3, 1, gomock.Any()).DoAndReturn(func(_ int, _ int, until *time.Time) error {
+					assert.Equal(t, inTenDays, until.Format("2006-01-02"))
+					return nil
+				})
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: `"snoozed_until":"` + inTenDays + `"`,
+		},
+		{
+			name:    "Skip the next birthday",
+			payload: subscribe.SnoozeRequestDto{SkipNext: true},
+			setupMock: func(mockSubscriptionRepo *mock_handler.MockSubscriptionRepository, mockUserRepo *mock_handler.MockUserRepository) {
+				mockSubscriptionRepo.EXPECT().GetSubscription(3, 1).Return(&subscription.Subscription{ID: 3, UserID: 1, RelatedUserID: 5}, nil)
+				mockUserRepo.EXPECT().GetUserByID(5).Return(celebrant, nil)
+				mockSubscriptionRepo.EXPECT().Snooze(3, 1, gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: `"snoozed_until":"` + nextBirthday.AddDate(0, 0, 1).Format("2006-01-02") + `"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockSubscriptionRepo := mock_handler.NewMockSubscriptionRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				userRepo:         mockUserRepo,
+				subscriptionRepo: mockSubscriptionRepo,
+				tokenManager:     mockTokenManager,
+			}
+
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, TimeZone: "UTC"}, nil)
+			tt.setupMock(mockSubscriptionRepo, mockUserRepo)
+
+			body, _ := json.Marshal(tt.payload)
+			req := httptest.NewRequest(http.MethodPost, "/api/subscriptions/3/snooze", bytes.NewBuffer(body))
+			req = mux.SetURLVars(req, map[string]string{"id": "3"})
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.SnoozeSubscription)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			responseBody, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(responseBody), tt.expectedOutput)
+		})
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	dbUser := func() *user.User {
+		return &user.User{ID: 1, Name: "John Doe", Email: "john@example.com", TimeZone: "Europe/Moscow", NotifyHour: 12}
+	}
+
+	testCases := []struct {
+		name           string
+		payload        string
+		setupMock      func(mockUserRepo *mock_handler.MockUserRepository)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:    "Invalid time zone",
+			payload: `{"time_zone": "Mars/Olympus"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(dbUser(), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid time zone",
+		},
+		{
+			name:    "Invalid notify hour",
+			payload: `{"notify_hour": 24}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(dbUser(), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Notify hour must be between 0 and 23",
+		},
+		{
+			name:    "Invalid delivery mode",
+			payload: `{"delivery_mode": "weekly"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(dbUser(), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid delivery mode",
+		},
+		{
+			name:    "Unsupported locale",
+			payload: `{"locale": "de"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(dbUser(), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Unsupported locale",
+		},
+		{
+			name:    "Successful update",
+			payload: `{"time_zone": "Asia/Tokyo", "notify_hour": 9}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(dbUser(), nil)
+				mockUserRepo.EXPECT().UpdateProfile(gomock.Any()).DoAndReturn(func(u *user.User) error {
+					assert.Equal(t, "Asia/Tokyo", u.TimeZone)
+					assert.Equal(t, 9, u.NotifyHour)
+					assert.Equal(t, "John Doe", u.Name)
+					return nil
+				})
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: `"time_zone":"Asia/Tokyo"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				userRepo:     mockUserRepo,
+				tokenManager: mockTokenManager,
+			}
+
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			tt.setupMock(mockUserRepo)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/me", strings.NewReader(tt.payload))
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.UpdateProfile)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestCreateTelegramLink(t *testing.T) {
+	testCases := []struct {
+		name           string
+		botName        string
+		setupMock      func(mockTelegramRepo *mock_handler.MockTelegramRepository, mockTokenManager *mock_auth.MockTokenManager)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name: "Invalid token",
+			setupMock: func(mockTelegramRepo *mock_handler.MockTelegramRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(nil, errors.New("invalid token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedOutput: "Unauthorized",
+		},
+		{
+			name: "Error saving link code",
+			setupMock: func(mockTelegramRepo *mock_handler.MockTelegramRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+				mockTelegramRepo.EXPECT().CreateLinkCode(gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedOutput: "Error saving link code",
+		},
+		{
+			name:    "Successful link code",
+			botName: "birthday_bot",
+			setupMock: func(mockTelegramRepo *mock_handler.MockTelegramRepository, mockTokenManager *mock_auth.MockTokenManager) {
+				mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+				mockTelegramRepo.EXPECT().CreateLinkCode(gomock.Any()).DoAndReturn(func(code telegram.LinkCode) error {
+					assert.Equal(t, 1, code.UserID)
+					assert.Len(t, code.Code, linkCodeLength)
+					assert.WithinDuration(t, time.Now().Add(linkCodeTTL), code.ExpiresAt, time.Minute)
+					return nil
+				})
+			},
+			expectedStatus: http.StatusCreated,
+			expectedOutput: `"link":"https://t.me/birthday_bot?start=`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTelegramRepo := mock_handler.NewMockTelegramRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				TelegramBotName: tt.botName,
+				telegramRepo:    mockTelegramRepo,
+				tokenManager:    mockTokenManager,
+			}
+
+			tt.setupMock(mockTelegramRepo, mockTokenManager)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/telegram/link", nil)
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.CreateTelegramLink)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestCreateWebhook(t *testing.T) {
+	testCases := []struct {
+		name           string
+		payload        string
+		setupMock      func(mockUserRepo *mock_handler.MockUserRepository, mockWebhookRepo *mock_handler.MockWebhookRepository)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:    "Invalid URL",
+			payload: `{"url": "ftp://example.com/hook"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid webhook URL",
+		},
+		{
+			name:    "Loopback target",
+			payload: `{"url": "http://127.0.0.1:5432/"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid webhook URL",
+		},
+		{
+			name:    "Cloud metadata target",
+			payload: `{"url": "http://169.254.169.254/latest/meta-data/"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid webhook URL",
+		},
+		{
+			name:    "Private network target",
+			payload: `{"url": "https://10.0.0.5/hook"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid webhook URL",
+		},
+		{
+			name:    "IPv6 loopback target",
+			payload: `{"url": "http://[::1]:8080/hook"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid webhook URL",
+		},
+		{
+			name:    "Localhost name",
+			payload: `{"url": "http://localhost:8080/hook"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid webhook URL",
+		},
+		{
+			name:    "Global webhook requires admin",
+			payload: `{"url": "https://example.com/hook", "is_global": true}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedOutput: "Forbidden",
+		},
+		{
+			name:    "Successful creation",
+			payload: `{"url": "https://example.com/hook"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+				mockWebhookRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(w *webhook.Webhook) error {
+					assert.Equal(t, 1, w.UserID)
+					assert.Len(t, w.Secret, 64)
+					w.ID = 3
+					return nil
+				})
+			},
+			expectedStatus: http.StatusCreated,
+			expectedOutput: `"id":3,"url":"https://example.com/hook","is_global":false`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockWebhookRepo := mock_handler.NewMockWebhookRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				userRepo:     mockUserRepo,
+				webhookRepo:  mockWebhookRepo,
+				tokenManager: mockTokenManager,
+			}
+
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			tt.setupMock(mockUserRepo, mockWebhookRepo)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(tt.payload))
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.CreateWebhook)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+			if res.StatusCode == http.StatusCreated {
+				assert.Contains(t, string(body), `"secret":"`)
+			}
+		})
+	}
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	statusCode := http.StatusBadGateway
+	testCases := []struct {
+		name           string
+		user           *user.User
+		setupMock      func(mockWebhookRepo *mock_handler.MockWebhookRepository)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name: "Webhook of another user",
+			user: &user.User{ID: 1},
+			setupMock: func(mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockWebhookRepo.EXPECT().GetByID(5).Return(&webhook.Webhook{ID: 5, UserID: 2}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedOutput: "Webhook not found",
+		},
+		{
+			name: "Admin sees global webhook",
+			user: &user.User{ID: 1, IsAdmin: true},
+			setupMock: func(mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockWebhookRepo.EXPECT().GetByID(5).Return(&webhook.Webhook{ID: 5, UserID: 2, IsGlobal: true}, nil)
+				mockWebhookRepo.EXPECT().GetDeliveries(5, webhookDeliveriesLimit).Return([]webhook.Delivery{
+					{ID: 9, WebhookID: 5, EventID: "2:reminder:1:2026:1", EventType: "birthday.reminder", StatusCode: &statusCode},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: `"event_id":"2:reminder:1:2026:1","event_type":"birthday.reminder","status_code":502`,
+		},
+		{
+			name: "Unknown webhook",
+			user: &user.User{ID: 1},
+			setupMock: func(mockWebhookRepo *mock_handler.MockWebhookRepository) {
+				mockWebhookRepo.EXPECT().GetByID(5).Return(nil, webhook.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedOutput: "Webhook not found",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockWebhookRepo := mock_handler.NewMockWebhookRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				userRepo:     mockUserRepo,
+				webhookRepo:  mockWebhookRepo,
+				tokenManager: mockTokenManager,
+			}
+
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			mockUserRepo.EXPECT().GetUserByID(1).Return(tt.user, nil)
+			tt.setupMock(mockWebhookRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/webhooks/5/deliveries", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "5"})
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.GetWebhookDeliveries)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestCreateAnnouncementChannel(t *testing.T) {
+	testCases := []struct {
+		name           string
+		payload        string
+		setupMock      func(mockUserRepo *mock_handler.MockUserRepository, mockAnnouncementRepo *mock_handler.MockAnnouncementRepository)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:    "Not admin",
+			payload: `{"name": "team", "url": "https://hooks.slack.com/services/T/B/X"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockAnnouncementRepo *mock_handler.MockAnnouncementRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedOutput: "Forbidden",
+		},
+		{
+			name:    "Invalid format",
+			payload: `{"name": "team", "url": "https://hooks.slack.com/services/T/B/X", "format": "{{.Nickname}}"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockAnnouncementRepo *mock_handler.MockAnnouncementRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, IsAdmin: true}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid format",
+		},
+		{
+			name:    "Invalid post hour",
+			payload: `{"name": "team", "url": "https://hooks.slack.com/services/T/B/X", "post_hour": 24}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockAnnouncementRepo *mock_handler.MockAnnouncementRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, IsAdmin: true}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Post hour must be between 0 and 23",
+		},
+		{
+			name:    "Successful creation with defaults",
+			payload: `{"name": "team", "url": "https://hooks.slack.com/services/T/B/X", "post_hour": 10}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository, mockAnnouncementRepo *mock_handler.MockAnnouncementRepository) {
+				mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1, IsAdmin: true}, nil)
+				mockAnnouncementRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(c *announcement.Channel) error {
+					assert.Equal(t, 1, c.CreatedBy)
+					assert.Equal(t, "UTC", c.TimeZone)
+					assert.NotEmpty(t, c.Format)
+					c.ID = 2
+					return nil
+				})
+			},
+			expectedStatus: http.StatusCreated,
+			expectedOutput: `"id":2,"name":"team"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockAnnouncementRepo := mock_handler.NewMockAnnouncementRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				userRepo:         mockUserRepo,
+				announcementRepo: mockAnnouncementRepo,
+				tokenManager:     mockTokenManager,
+			}
+
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			tt.setupMock(mockUserRepo, mockAnnouncementRepo)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/announcements", strings.NewReader(tt.payload))
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.CreateAnnouncementChannel)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestGetNotifications(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		setupMock      func(mockInboxRepo *mock_handler.MockInboxRepository)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:           "Invalid limit",
+			query:          "?limit=500",
+			setupMock:      func(mockInboxRepo *mock_handler.MockInboxRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "limit must be between 1 and 100",
+		},
+		{
+			name:  "Unread page",
+			query: "?unread=true&limit=1&offset=1",
+			setupMock: func(mockInboxRepo *mock_handler.MockInboxRepository) {
+				mockInboxRepo.EXPECT().List(1, true, 1, 1).Return([]inbox.Item{
+					{ID: 7, Kind: "reminder", Title: "Напоминание", Body: "Завтра день рождения у Anna!", CelebrantID: 2},
+				}, nil)
+				mockInboxRepo.EXPECT().Counts(1).Return(5, 3, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: `"celebrant_id":2,"read":false,"created_at":"0001-01-01T00:00:00Z"}],"total":3,"unread":3,"limit":1,"offset":1`,
+		},
+		{
+			name:  "Default page",
+			query: "",
+			setupMock: func(mockInboxRepo *mock_handler.MockInboxRepository) {
+				mockInboxRepo.EXPECT().List(1, false, 20, 0).Return(nil, nil)
+				mockInboxRepo.EXPECT().Counts(1).Return(0, 0, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: `{"items":[],"total":0,"unread":0,"limit":20,"offset":0}`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockInboxRepo := mock_handler.NewMockInboxRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				userRepo:     mockUserRepo,
+				inboxRepo:    mockInboxRepo,
+				tokenManager: mockTokenManager,
+			}
+
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			tt.setupMock(mockInboxRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/notifications"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.GetNotifications)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	testCases := []struct {
+		name           string
+		repoErr        error
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:           "Marked",
+			expectedStatus: http.StatusOK,
+			expectedOutput: "Notification marked as read",
+		},
+		{
+			name:           "Foreign or missing notification",
+			repoErr:        inbox.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedOutput: "Notification not found",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockInboxRepo := mock_handler.NewMockInboxRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				userRepo:     mockUserRepo,
+				inboxRepo:    mockInboxRepo,
+				tokenManager: mockTokenManager,
+			}
+
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			mockInboxRepo.EXPECT().MarkRead(1, 7).Return(tt.repoErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/notifications/7/read", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "7"})
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.MarkNotificationRead)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestStreamNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvents := mock_handler.NewMockEventStream(ctrl)
+	mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+	handler := &Handler{
+		events:       mockEvents,
+		tokenManager: mockTokenManager,
+	}
+
+	events := make(chan stream.Event, 1)
+	events <- stream.Event{Type: stream.EventNotification, UserID: 1, Data: json.RawMessage(`{"id":7}`)}
+	close(events)
+	unsubscribed := false
+
+	// токен из cookie: EventSource не передаёт заголовок Authorization
+	mockTokenManager.EXPECT().ParseJWT("cookie.token").Return(&auth.Claims{UserID: 1}, nil)
+	mockEvents.EXPECT().Subscribe(1).Return(events, func() { unsubscribed = true })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/notifications/stream", nil)
+	req.AddCookie(&http.Cookie{Name: "jwt_token", Value: "cookie.token"})
+	w := httptest.NewRecorder()
+
+	handler.Authenticate(http.HandlerFunc(handler.StreamNotifications)).ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	body, _ := io.ReadAll(res.Body)
+	assert.Contains(t, string(body), "event: notification\ndata: {\"id\":7}\n\n")
+	assert.True(t, unsubscribed)
+}
+
+func TestStreamNotificationsClosesWhenTokenRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvents := mock_handler.NewMockEventStream(ctrl)
+	mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+	handler := &Handler{
+		events:       mockEvents,
+		tokenManager: mockTokenManager,
+	}
+
+	defer func(d time.Duration) { streamHeartbeat = d }(streamHeartbeat)
+	streamHeartbeat = 10 * time.Millisecond
+
+	gomock.InOrder(
+		mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil),
+		// после /api/logout следующий пинг уже не проходит проверку
+		mockTokenManager.EXPECT().ParseJWT("valid.token").Return(nil, errors.New("token revoked")),
+	)
+	mockEvents.EXPECT().Subscribe(1).Return(make(chan stream.Event), func() {})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/notifications/stream", nil)
+	req.Header.Set("Authorization", "Bearer valid.token")
+	w := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		handler.Authenticate(http.HandlerFunc(handler.StreamNotifications)).ServeHTTP(w, req)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream stayed open after the token was revoked")
+	}
+	assert.NotContains(t, w.Body.String(), ": ping")
+}
+
+func TestStreamNotificationsClosesWhenTokenExpires(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvents := mock_handler.NewMockEventStream(ctrl)
+	mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+	handler := &Handler{
+		events:       mockEvents,
+		tokenManager: mockTokenManager,
+	}
+
+	claims := &auth.Claims{
+		UserID:           1,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(50 * time.Millisecond))},
+	}
+	mockTokenManager.EXPECT().ParseJWT("valid.token").Return(claims, nil)
+	mockEvents.EXPECT().Subscribe(1).Return(make(chan stream.Event), func() {})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/notifications/stream", nil)
+	req.Header.Set("Authorization", "Bearer valid.token")
+	w := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		handler.Authenticate(http.HandlerFunc(handler.StreamNotifications)).ServeHTTP(w, req)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream stayed open after the token expired")
+	}
+}
+
+func TestCreatePushSubscription(t *testing.T) {
+	const keys = `"keys": {"p256dh": "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4", "auth": "BTBZMqHH6r4Tts7J_aSIgg"}`
+
+	testCases := []struct {
+		name           string
+		payload        string
+		setupMock      func(mockPushRepo *mock_handler.MockPushRepository)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:           "Invalid endpoint",
+			payload:        `{"endpoint": "not a url", ` + keys + `}`,
+			setupMock:      func(mockPushRepo *mock_handler.MockPushRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid push endpoint",
+		},
+		{
+			name:           "Plain http endpoint",
+			payload:        `{"endpoint": "http://push.example.net/abc", ` + keys + `}`,
+			setupMock:      func(mockPushRepo *mock_handler.MockPushRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid push endpoint",
+		},
+		{
+			name:           "Internal endpoint",
+			payload:        `{"endpoint": "https://127.0.0.1:8443/push", ` + keys + `}`,
+			setupMock:      func(mockPushRepo *mock_handler.MockPushRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid push endpoint",
+		},
+		{
+			name:           "Metadata endpoint",
+			payload:        `{"endpoint": "https://169.254.169.254/push", ` + keys + `}`,
+			setupMock:      func(mockPushRepo *mock_handler.MockPushRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid push endpoint",
+		},
+		{
+			name:           "Invalid keys",
+			payload:        `{"endpoint": "https://push.example.net/abc", "keys": {"p256dh": "AAAA", "auth": "BTBZMqHH6r4Tts7J_aSIgg"}}`,
+			setupMock:      func(mockPushRepo *mock_handler.MockPushRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Invalid subscription keys",
+		},
+		{
+			name:    "Successful registration",
+			payload: `{"endpoint": "https://push.example.net/abc", ` + keys + `, "device": " Firefox "}`,
+			setupMock: func(mockPushRepo *mock_handler.MockPushRepository) {
+				mockPushRepo.EXPECT().Save(gomock.Any()).DoAndReturn(func(s *push.Subscription) error {
+					assert.Equal(t, 1, s.UserID)
+					assert.Equal(t, "BTBZMqHH6r4Tts7J_aSIgg", s.Auth)
+					s.ID = 4
+					return nil
+				})
+			},
+			expectedStatus: http.StatusCreated,
+			expectedOutput: `"id":4,"endpoint":"https://push.example.net/abc","device":"Firefox"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockPushRepo := mock_handler.NewMockPushRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				VAPIDPublicKey: "BP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A8",
+				userRepo:       mockUserRepo,
+				pushRepo:       mockPushRepo,
+				tokenManager:   mockTokenManager,
+			}
+
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{ID: 1}, nil)
+			tt.setupMock(mockPushRepo)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/push/subscriptions", strings.NewReader(tt.payload))
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.CreatePushSubscription)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
+		})
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	testCases := []struct {
+		name           string
+		payload        string
+		setupMock      func(mockUserRepo *mock_handler.MockUserRepository)
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:           "Unknown channel",
+			payload:        `{"channels": {"pigeon": true}}`,
+			setupMock:      func(mockUserRepo *mock_handler.MockUserRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "Unknown channel: pigeon",
+		},
+		{
+			name:           "Delivery hour inside quiet hours",
+			payload:        `{"quiet_hours": {"start": 22, "end": 8}, "delivery_hour": 7}`,
+			setupMock:      func(mockUserRepo *mock_handler.MockUserRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "delivery hour must be outside quiet hours",
+		},
+		{
+			name:    "Partial update keeps other channels",
+			payload: `{"channels": {"telegram": false}, "quiet_hours": {"start": 22, "end": 8}, "language": "en"}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository) {
+				mockUserRepo.EXPECT().UpdatePreferences(gomock.Any()).DoAndReturn(func(u *user.User) error {
+					assert.ElementsMatch(t, []string{"push", "telegram"}, u.DisabledChannels)
+					assert.Equal(t, 22, u.QuietStart)
+					assert.Equal(t, 8, u.QuietEnd)
+					assert.Equal(t, "en", u.Locale)
+					return nil
+				})
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: `"channels":{"email":true,"inapp":true,"push":false,"telegram":false,"webhook":true},"delivery_hour":12,"quiet_hours":{"start":22,"end":8},"delivery_mode":"individual","language":"en"`,
+		},
+		{
+			name:    "Equal bounds turn quiet hours off",
+			payload: `{"quiet_hours": {"start": 0, "end": 0}}`,
+			setupMock: func(mockUserRepo *mock_handler.MockUserRepository) {
+				mockUserRepo.EXPECT().UpdatePreferences(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedOutput: `"quiet_hours":null`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_handler.NewMockUserRepository(ctrl)
+			mockTokenManager := mock_auth.NewMockTokenManager(ctrl)
+			handler := &Handler{
+				userRepo:     mockUserRepo,
+				tokenManager: mockTokenManager,
+			}
+
+			mockTokenManager.EXPECT().ParseJWT("valid.token").Return(&auth.Claims{UserID: 1}, nil)
+			mockUserRepo.EXPECT().GetUserByID(1).Return(&user.User{
+				ID:               1,
+				NotifyHour:       12,
+				Locale:           "ru",
+				DeliveryMode:     user.DeliveryIndividual,
+				DisabledChannels: []string{"push"},
+				QuietStart:       23,
+				QuietEnd:         7,
+			}, nil)
+			tt.setupMock(mockUserRepo)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/me/preferences", strings.NewReader(tt.payload))
+			req.Header.Set("Authorization", "Bearer valid.token")
+			w := httptest.NewRecorder()
+
+			handler.Authenticate(http.HandlerFunc(handler.UpdatePreferences)).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			assert.Contains(t, string(body), tt.expectedOutput)
 		})
 	}
 }
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"birthdayReminder/internal/handler/inbox_notification"
+	"birthdayReminder/internal/repository/inbox"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultInboxLimit = 20
+	maxInboxLimit     = 100
+)
+
+// parsePage читает limit и offset из строки запроса.
+func parsePage(r *http.Request) (limit int, offset int, err error) {
+	limit = defaultInboxLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxInboxLimit {
+			return 0, 0, errors.New("limit must be between 1 and 100")
+		}
+	}
+	if raw := r.URL.Query().Get("offset"); raw != "" {
+		offset, err = strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative number")
+		}
+	}
+	return limit, offset, nil
+}
+
+// GetNotifications /api/notifications
+func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling get notifications request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unreadOnly := false
+	if raw := r.URL.Query().Get("unread"); raw != "" {
+		unreadOnly, err = strconv.ParseBool(raw)
+		if err != nil {
+			http.Error(w, "Invalid unread filter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	items, err := h.inboxRepo.List(dbUser.ID, unreadOnly, limit, offset)
+	if err != nil {
+		log.Println("Error fetching notifications:", err)
+		http.Error(w, "Error fetching notifications", http.StatusInternalServerError)
+		return
+	}
+
+	total, unread, err := h.inboxRepo.Counts(dbUser.ID)
+	if err != nil {
+		log.Println("Error counting notifications:", err)
+		http.Error(w, "Error fetching notifications", http.StatusInternalServerError)
+		return
+	}
+	if unreadOnly {
+		total = unread
+	}
+
+	list := inbox_notification.ListDto{
+		Items:  make([]inbox_notification.ItemDto, 0, len(items)),
+		Total:  total,
+		Unread: unread,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, item := range items {
+		list.Items = append(list.Items, inbox_notification.ItemDto{
+			ID:          item.ID,
+			Kind:        item.Kind,
+			Title:       item.Title,
+			Body:        item.Body,
+			CelebrantID: item.CelebrantID,
+			Read:        item.ReadAt != nil,
+			ReadAt:      item.ReadAt,
+			CreatedAt:   item.CreatedAt,
+		})
+	}
+
+	response, err := json.Marshal(list)
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// MarkNotificationRead /api/notifications/{id}/read
+func (h *Handler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling mark notification read request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.inboxRepo.MarkRead(dbUser.ID, id); err != nil {
+		if errors.Is(err, inbox.ErrNotFound) {
+			http.Error(w, "Notification not found", http.StatusNotFound)
+		} else {
+			log.Println("Error marking notification read:", err)
+			http.Error(w, "Error marking notification read", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte("Notification marked as read"))
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// MarkAllNotificationsRead /api/notifications/read
+func (h *Handler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling mark all notifications read request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.inboxRepo.MarkAllRead(dbUser.ID)
+	if err != nil {
+		log.Println("Error marking notifications read:", err)
+		http.Error(w, "Error marking notifications read", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := json.Marshal(inbox_notification.MarkAllReadDto{Updated: updated})
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
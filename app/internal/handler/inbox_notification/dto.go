@@ -0,0 +1,27 @@
+package inbox_notification
+
+import "time"
+
+type ItemDto struct {
+	ID          int        `json:"id"`
+	Kind        string     `json:"kind"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	CelebrantID int        `json:"celebrant_id,omitempty"`
+	Read        bool       `json:"read"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type ListDto struct {
+	Items []ItemDto `json:"items"`
+	// Total - число уведомлений с учётом фильтра, Unread - непрочитанных всего (для значка на колокольчике).
+	Total  int `json:"total"`
+	Unread int `json:"unread"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+type MarkAllReadDto struct {
+	Updated int64 `json:"updated"`
+}
@@ -5,11 +5,48 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func InitRoutes(router *mux.Router, userRepo UserRepository, subscriptionRepo SubscriptionRepository, tokenManager auth.TokenManager) {
-	h := New(userRepo, subscriptionRepo, tokenManager)
+func InitRoutes(router *mux.Router, userRepo UserRepository, subscriptionRepo SubscriptionRepository, notificationRepo NotificationRepository, telegramRepo TelegramRepository, webhookRepo WebhookRepository, announcementRepo AnnouncementRepository, inboxRepo InboxRepository, pushRepo PushRepository, refreshRepo RefreshTokenRepository, mailer VerificationMailer, events EventStream, tokenManager auth.TokenManager) {
+	h := New(userRepo, subscriptionRepo, notificationRepo, telegramRepo, webhookRepo, announcementRepo, inboxRepo, pushRepo, refreshRepo, mailer, events, tokenManager)
 	router.HandleFunc("/api/registration", h.Register).Methods("POST")
 	router.HandleFunc("/api/login", h.Login).Methods("POST")
-	router.HandleFunc("/api/subscribe", h.Subscribe).Methods("POST")
-	router.HandleFunc("/api/available", h.GetAvailableUsers).Methods("GET")
-	router.HandleFunc("/api/unsubscribe", h.Unsubscribe).Methods("POST")
+	router.HandleFunc("/api/token/refresh", h.RefreshToken).Methods("POST")
+	router.HandleFunc("/api/push/vapid-key", h.GetVAPIDKey).Methods("GET")
+	router.HandleFunc("/.well-known/jwks.json", h.GetJWKS).Methods("GET")
+	router.HandleFunc("/api/verify-email", h.VerifyEmail).Methods("GET", "POST")
+
+	// остальные маршруты доступны только с действующим токеном
+	protected := router.NewRoute().Subrouter()
+	protected.Use(h.Authenticate)
+	protected.HandleFunc("/api/logout", h.Logout).Methods("POST")
+	protected.HandleFunc("/api/logout-all", h.LogoutAll).Methods("POST")
+	protected.HandleFunc("/api/verify-email/resend", h.ResendVerification).Methods("POST")
+	protected.HandleFunc("/api/subscribe", h.Subscribe).Methods("POST")
+	protected.HandleFunc("/api/me", h.GetProfile).Methods("GET")
+	protected.HandleFunc("/api/me", h.UpdateProfile).Methods("PUT")
+	protected.HandleFunc("/api/me/preferences", h.GetPreferences).Methods("GET")
+	protected.HandleFunc("/api/me/preferences", h.UpdatePreferences).Methods("PUT")
+	protected.HandleFunc("/api/available", h.GetAvailableUsers).Methods("GET")
+	protected.HandleFunc("/api/unsubscribe", h.Unsubscribe).Methods("POST")
+	protected.HandleFunc("/api/subscriptions", h.GetSubscriptions).Methods("GET")
+	protected.HandleFunc("/api/subscriptions/{id:[0-9]+}", h.UpdateSubscription).Methods("PUT")
+	protected.HandleFunc("/api/subscriptions/{id:[0-9]+}/snooze", h.SnoozeSubscription).Methods("POST")
+	protected.HandleFunc("/api/subscriptions/{id:[0-9]+}/snooze", h.UnsnoozeSubscription).Methods("DELETE")
+	protected.HandleFunc("/api/telegram/link", h.CreateTelegramLink).Methods("POST")
+	protected.HandleFunc("/api/telegram/link", h.DeleteTelegramLink).Methods("DELETE")
+	protected.HandleFunc("/api/notifications", h.GetNotifications).Methods("GET")
+	protected.HandleFunc("/api/notifications/stream", h.StreamNotifications).Methods("GET")
+	protected.HandleFunc("/api/notifications/read", h.MarkAllNotificationsRead).Methods("POST")
+	protected.HandleFunc("/api/notifications/{id:[0-9]+}/read", h.MarkNotificationRead).Methods("POST")
+	protected.HandleFunc("/api/push/subscriptions", h.CreatePushSubscription).Methods("POST")
+	protected.HandleFunc("/api/push/subscriptions", h.GetPushSubscriptions).Methods("GET")
+	protected.HandleFunc("/api/push/subscriptions/{id:[0-9]+}", h.DeletePushSubscription).Methods("DELETE")
+	protected.HandleFunc("/api/webhooks", h.CreateWebhook).Methods("POST")
+	protected.HandleFunc("/api/webhooks", h.GetWebhooks).Methods("GET")
+	protected.HandleFunc("/api/webhooks/{id:[0-9]+}", h.DeleteWebhook).Methods("DELETE")
+	protected.HandleFunc("/api/webhooks/{id:[0-9]+}/deliveries", h.GetWebhookDeliveries).Methods("GET")
+	protected.HandleFunc("/api/admin/notifications/dead", h.GetDeadNotifications).Methods("GET")
+	protected.HandleFunc("/api/admin/notifications/{id:[0-9]+}/retry", h.RetryNotification).Methods("POST")
+	protected.HandleFunc("/api/admin/announcements", h.CreateAnnouncementChannel).Methods("POST")
+	protected.HandleFunc("/api/admin/announcements", h.GetAnnouncementChannels).Methods("GET")
+	protected.HandleFunc("/api/admin/announcements/{id:[0-9]+}", h.DeleteAnnouncementChannel).Methods("DELETE")
 }
@@ -4,3 +4,15 @@ type Dto struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// ResponseDto - пара токенов, которую выдают вход и обновление токена.
+type ResponseDto struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshRequestDto struct {
+	RefreshToken string `json:"refresh_token"`
+}
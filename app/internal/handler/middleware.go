@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"birthdayReminder/internal/handler/auth"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+type contextKey int
+
+const claimsKey contextKey = iota
+
+var (
+	errTokenMissing      = errors.New("authentication token missing")
+	errInvalidAuthScheme = errors.New("authorization header must use the Bearer scheme")
+)
+
+// tokenFromRequest извлекает JWT из заголовка "Authorization: Bearer <token>",
+// а если заголовка нет - из cookie jwt_token, которую ставит /api/login.
+func tokenFromRequest(r *http.Request) (string, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		token = strings.TrimSpace(token)
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return "", errInvalidAuthScheme
+		}
+		return token, nil
+	}
+
+	if cookie, err := r.Cookie("jwt_token"); err == nil && cookie.Value != "" {
+		return cookie.Value, nil
+	}
+	return "", errTokenMissing
+}
+
+// Authenticate - middleware защищенных маршрутов: проверяет токен и кладет его claims в контекст запроса.
+func (h *Handler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenStr, err := tokenFromRequest(r)
+		if errors.Is(err, errTokenMissing) {
+			http.Error(w, "Authorization header missing", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Invalid Authorization header", http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := h.tokenManager.ParseJWT(tokenStr)
+		if err != nil {
+			log.Println("Error parsing JWT:", err)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if claims.UserID == 0 {
+			log.Println("Invalid user ID in token")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), claimsKey, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// claimsFromContext возвращает claims токена, положенные в контекст Authenticate.
+func claimsFromContext(ctx context.Context) (*auth.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*auth.Claims)
+	return claims, ok && claims.UserID != 0
+}
+
+// userIDFromContext возвращает ID пользователя, прошедшего Authenticate.
+func userIDFromContext(ctx context.Context) (int, bool) {
+	claims, ok := claimsFromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+// authenticatedClaims читает claims из контекста; если маршрут не закрыт Authenticate, отвечает 401.
+func (h *Handler) authenticatedClaims(w http.ResponseWriter, r *http.Request) (*auth.Claims, bool) {
+	claims, ok := claimsFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	return claims, true
+}
+
+// authenticatedUserID читает ID пользователя из контекста; если маршрут не закрыт Authenticate, отвечает 401.
+func (h *Handler) authenticatedUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	claims, ok := h.authenticatedClaims(w, r)
+	if !ok {
+		return 0, false
+	}
+	return claims.UserID, true
+}
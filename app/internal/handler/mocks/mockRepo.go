@@ -5,8 +5,18 @@
 package mock_handler
 
 import (
-	user "birthdayReminder/app/internal/repository/user"
+	announcement "birthdayReminder/internal/repository/announcement"
+	inbox "birthdayReminder/internal/repository/inbox"
+	notification "birthdayReminder/internal/repository/notification"
+	push "birthdayReminder/internal/repository/push"
+	refresh "birthdayReminder/internal/repository/refresh"
+	subscription "birthdayReminder/internal/repository/subscription"
+	telegram "birthdayReminder/internal/repository/telegram"
+	user "birthdayReminder/internal/repository/user"
+	webhook "birthdayReminder/internal/repository/webhook"
+	stream "birthdayReminder/internal/stream"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -93,19 +103,77 @@ func (mr *MockUserRepositoryMockRecorder) GetUserByEmail(email interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepository)(nil).GetUserByEmail), email)
 }
 
-// GetUsersWithBirthdayTomorrow mocks base method.
-func (m *MockUserRepository) GetUsersWithBirthdayTomorrow() ([]user.User, error) {
+// GetUserByID mocks base method.
+func (m *MockUserRepository) GetUserByID(id int) (*user.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUsersWithBirthdayTomorrow")
-	ret0, _ := ret[0].([]user.User)
+	ret := m.ctrl.Call(m, "GetUserByID", id)
+	ret0, _ := ret[0].(*user.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUsersWithBirthdayTomorrow indicates an expected call of GetUsersWithBirthdayTomorrow.
-func (mr *MockUserRepositoryMockRecorder) GetUsersWithBirthdayTomorrow() *gomock.Call {
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserRepositoryMockRecorder) GetUserByID(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersWithBirthdayTomorrow", reflect.TypeOf((*MockUserRepository)(nil).GetUsersWithBirthdayTomorrow))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepository)(nil).GetUserByID), id)
+}
+
+// StartVerification mocks base method.
+func (m *MockUserRepository) StartVerification(userID int, tokenID string, interval time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartVerification", userID, tokenID, interval)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartVerification indicates an expected call of StartVerification.
+func (mr *MockUserRepositoryMockRecorder) StartVerification(userID, tokenID, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartVerification", reflect.TypeOf((*MockUserRepository)(nil).StartVerification), userID, tokenID, interval)
+}
+
+// UpdatePreferences mocks base method.
+func (m *MockUserRepository) UpdatePreferences(user *user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePreferences", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePreferences indicates an expected call of UpdatePreferences.
+func (mr *MockUserRepositoryMockRecorder) UpdatePreferences(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePreferences", reflect.TypeOf((*MockUserRepository)(nil).UpdatePreferences), user)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockUserRepository) UpdateProfile(user *user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserRepositoryMockRecorder) UpdateProfile(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserRepository)(nil).UpdateProfile), user)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserRepository) VerifyEmail(userID int, email, tokenID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", userID, email, tokenID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserRepositoryMockRecorder) VerifyEmail(userID, email, tokenID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserRepository)(nil).VerifyEmail), userID, email, tokenID)
 }
 
 // MockSubscriptionRepository is a mock of SubscriptionRepository interface.
@@ -132,17 +200,61 @@ func (m *MockSubscriptionRepository) EXPECT() *MockSubscriptionRepositoryMockRec
 }
 
 // CreateSubscription mocks base method.
-func (m *MockSubscriptionRepository) CreateSubscription(userID, relatedUserID int) error {
+func (m *MockSubscriptionRepository) CreateSubscription(userID, relatedUserID int, offsets []int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSubscription", userID, relatedUserID)
+	ret := m.ctrl.Call(m, "CreateSubscription", userID, relatedUserID, offsets)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateSubscription indicates an expected call of CreateSubscription.
-func (mr *MockSubscriptionRepositoryMockRecorder) CreateSubscription(userID, relatedUserID interface{}) *gomock.Call {
+func (mr *MockSubscriptionRepositoryMockRecorder) CreateSubscription(userID, relatedUserID, offsets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockSubscriptionRepository)(nil).CreateSubscription), userID, relatedUserID, offsets)
+}
+
+// GetSubscription mocks base method.
+func (m *MockSubscriptionRepository) GetSubscription(id, userID int) (*subscription.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", id, userID)
+	ret0, _ := ret[0].(*subscription.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetSubscription(id, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockSubscriptionRepository)(nil).CreateSubscription), userID, relatedUserID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetSubscription), id, userID)
+}
+
+// GetSubscriptions mocks base method.
+func (m *MockSubscriptionRepository) GetSubscriptions(userID int) ([]subscription.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptions", userID)
+	ret0, _ := ret[0].([]subscription.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptions indicates an expected call of GetSubscriptions.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetSubscriptions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptions", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetSubscriptions), userID)
+}
+
+// Snooze mocks base method.
+func (m *MockSubscriptionRepository) Snooze(id, userID int, until *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snooze", id, userID, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Snooze indicates an expected call of Snooze.
+func (mr *MockSubscriptionRepositoryMockRecorder) Snooze(id, userID, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snooze", reflect.TypeOf((*MockSubscriptionRepository)(nil).Snooze), id, userID, until)
 }
 
 // UnsubscribeUser mocks base method.
@@ -158,3 +270,615 @@ func (mr *MockSubscriptionRepositoryMockRecorder) UnsubscribeUser(userID, relate
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeUser", reflect.TypeOf((*MockSubscriptionRepository)(nil).UnsubscribeUser), userID, relatedUserID)
 }
+
+// UpdateOffsets mocks base method.
+func (m *MockSubscriptionRepository) UpdateOffsets(id, userID int, offsets []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOffsets", id, userID, offsets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOffsets indicates an expected call of UpdateOffsets.
+func (mr *MockSubscriptionRepositoryMockRecorder) UpdateOffsets(id, userID, offsets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOffsets", reflect.TypeOf((*MockSubscriptionRepository)(nil).UpdateOffsets), id, userID, offsets)
+}
+
+// MockNotificationRepository is a mock of NotificationRepository interface.
+type MockNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepositoryMockRecorder
+}
+
+// MockNotificationRepositoryMockRecorder is the mock recorder for MockNotificationRepository.
+type MockNotificationRepositoryMockRecorder struct {
+	mock *MockNotificationRepository
+}
+
+// NewMockNotificationRepository creates a new mock instance.
+func NewMockNotificationRepository(ctrl *gomock.Controller) *MockNotificationRepository {
+	mock := &MockNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepository) EXPECT() *MockNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetDead mocks base method.
+func (m *MockNotificationRepository) GetDead() ([]notification.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDead")
+	ret0, _ := ret[0].([]notification.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDead indicates an expected call of GetDead.
+func (mr *MockNotificationRepositoryMockRecorder) GetDead() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDead", reflect.TypeOf((*MockNotificationRepository)(nil).GetDead))
+}
+
+// Requeue mocks base method.
+func (m *MockNotificationRepository) Requeue(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Requeue", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Requeue indicates an expected call of Requeue.
+func (mr *MockNotificationRepositoryMockRecorder) Requeue(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Requeue", reflect.TypeOf((*MockNotificationRepository)(nil).Requeue), id)
+}
+
+// MockTelegramRepository is a mock of TelegramRepository interface.
+type MockTelegramRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTelegramRepositoryMockRecorder
+}
+
+// MockTelegramRepositoryMockRecorder is the mock recorder for MockTelegramRepository.
+type MockTelegramRepositoryMockRecorder struct {
+	mock *MockTelegramRepository
+}
+
+// NewMockTelegramRepository creates a new mock instance.
+func NewMockTelegramRepository(ctrl *gomock.Controller) *MockTelegramRepository {
+	mock := &MockTelegramRepository{ctrl: ctrl}
+	mock.recorder = &MockTelegramRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTelegramRepository) EXPECT() *MockTelegramRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateLinkCode mocks base method.
+func (m *MockTelegramRepository) CreateLinkCode(code telegram.LinkCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLinkCode", code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLinkCode indicates an expected call of CreateLinkCode.
+func (mr *MockTelegramRepositoryMockRecorder) CreateLinkCode(code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLinkCode", reflect.TypeOf((*MockTelegramRepository)(nil).CreateLinkCode), code)
+}
+
+// Unlink mocks base method.
+func (m *MockTelegramRepository) Unlink(userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlink", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlink indicates an expected call of Unlink.
+func (mr *MockTelegramRepositoryMockRecorder) Unlink(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlink", reflect.TypeOf((*MockTelegramRepository)(nil).Unlink), userID)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookRepository) Create(w *webhook.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookRepositoryMockRecorder) Create(w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookRepository)(nil).Create), w)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookRepository) Delete(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookRepository)(nil).Delete), id)
+}
+
+// GetByID mocks base method.
+func (m *MockWebhookRepository) GetByID(id int) (*webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWebhookRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWebhookRepository)(nil).GetByID), id)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhookRepository) GetDeliveries(webhookID, limit int) ([]webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", webhookID, limit)
+	ret0, _ := ret[0].([]webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) GetDeliveries(webhookID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).GetDeliveries), webhookID, limit)
+}
+
+// GetVisible mocks base method.
+func (m *MockWebhookRepository) GetVisible(userID int, isAdmin bool) ([]webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVisible", userID, isAdmin)
+	ret0, _ := ret[0].([]webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVisible indicates an expected call of GetVisible.
+func (mr *MockWebhookRepositoryMockRecorder) GetVisible(userID, isAdmin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVisible", reflect.TypeOf((*MockWebhookRepository)(nil).GetVisible), userID, isAdmin)
+}
+
+// MockAnnouncementRepository is a mock of AnnouncementRepository interface.
+type MockAnnouncementRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnnouncementRepositoryMockRecorder
+}
+
+// MockAnnouncementRepositoryMockRecorder is the mock recorder for MockAnnouncementRepository.
+type MockAnnouncementRepositoryMockRecorder struct {
+	mock *MockAnnouncementRepository
+}
+
+// NewMockAnnouncementRepository creates a new mock instance.
+func NewMockAnnouncementRepository(ctrl *gomock.Controller) *MockAnnouncementRepository {
+	mock := &MockAnnouncementRepository{ctrl: ctrl}
+	mock.recorder = &MockAnnouncementRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnnouncementRepository) EXPECT() *MockAnnouncementRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAnnouncementRepository) Create(c *announcement.Channel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAnnouncementRepositoryMockRecorder) Create(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAnnouncementRepository)(nil).Create), c)
+}
+
+// Delete mocks base method.
+func (m *MockAnnouncementRepository) Delete(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAnnouncementRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAnnouncementRepository)(nil).Delete), id)
+}
+
+// GetAll mocks base method.
+func (m *MockAnnouncementRepository) GetAll() ([]announcement.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]announcement.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAnnouncementRepositoryMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAnnouncementRepository)(nil).GetAll))
+}
+
+// MockInboxRepository is a mock of InboxRepository interface.
+type MockInboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInboxRepositoryMockRecorder
+}
+
+// MockInboxRepositoryMockRecorder is the mock recorder for MockInboxRepository.
+type MockInboxRepositoryMockRecorder struct {
+	mock *MockInboxRepository
+}
+
+// NewMockInboxRepository creates a new mock instance.
+func NewMockInboxRepository(ctrl *gomock.Controller) *MockInboxRepository {
+	mock := &MockInboxRepository{ctrl: ctrl}
+	mock.recorder = &MockInboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInboxRepository) EXPECT() *MockInboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Counts mocks base method.
+func (m *MockInboxRepository) Counts(userID int) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Counts", userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Counts indicates an expected call of Counts.
+func (mr *MockInboxRepositoryMockRecorder) Counts(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Counts", reflect.TypeOf((*MockInboxRepository)(nil).Counts), userID)
+}
+
+// List mocks base method.
+func (m *MockInboxRepository) List(userID int, unreadOnly bool, limit, offset int) ([]inbox.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", userID, unreadOnly, limit, offset)
+	ret0, _ := ret[0].([]inbox.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockInboxRepositoryMockRecorder) List(userID, unreadOnly, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInboxRepository)(nil).List), userID, unreadOnly, limit, offset)
+}
+
+// MarkAllRead mocks base method.
+func (m *MockInboxRepository) MarkAllRead(userID int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllRead", userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllRead indicates an expected call of MarkAllRead.
+func (mr *MockInboxRepositoryMockRecorder) MarkAllRead(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllRead", reflect.TypeOf((*MockInboxRepository)(nil).MarkAllRead), userID)
+}
+
+// MarkRead mocks base method.
+func (m *MockInboxRepository) MarkRead(userID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockInboxRepositoryMockRecorder) MarkRead(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockInboxRepository)(nil).MarkRead), userID, id)
+}
+
+// MockEventStream is a mock of EventStream interface.
+type MockEventStream struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventStreamMockRecorder
+}
+
+// MockEventStreamMockRecorder is the mock recorder for MockEventStream.
+type MockEventStreamMockRecorder struct {
+	mock *MockEventStream
+}
+
+// NewMockEventStream creates a new mock instance.
+func NewMockEventStream(ctrl *gomock.Controller) *MockEventStream {
+	mock := &MockEventStream{ctrl: ctrl}
+	mock.recorder = &MockEventStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventStream) EXPECT() *MockEventStreamMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventStream) Publish(e stream.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventStreamMockRecorder) Publish(e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventStream)(nil).Publish), e)
+}
+
+// Subscribe mocks base method.
+func (m *MockEventStream) Subscribe(userID int) (<-chan stream.Event, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", userID)
+	ret0, _ := ret[0].(<-chan stream.Event)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventStreamMockRecorder) Subscribe(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventStream)(nil).Subscribe), userID)
+}
+
+// MockPushRepository is a mock of PushRepository interface.
+type MockPushRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPushRepositoryMockRecorder
+}
+
+// MockPushRepositoryMockRecorder is the mock recorder for MockPushRepository.
+type MockPushRepositoryMockRecorder struct {
+	mock *MockPushRepository
+}
+
+// NewMockPushRepository creates a new mock instance.
+func NewMockPushRepository(ctrl *gomock.Controller) *MockPushRepository {
+	mock := &MockPushRepository{ctrl: ctrl}
+	mock.recorder = &MockPushRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPushRepository) EXPECT() *MockPushRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockPushRepository) Delete(userID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPushRepositoryMockRecorder) Delete(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPushRepository)(nil).Delete), userID, id)
+}
+
+// GetByUser mocks base method.
+func (m *MockPushRepository) GetByUser(userID int) ([]push.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", userID)
+	ret0, _ := ret[0].([]push.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockPushRepositoryMockRecorder) GetByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockPushRepository)(nil).GetByUser), userID)
+}
+
+// Save mocks base method.
+func (m *MockPushRepository) Save(s *push.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockPushRepositoryMockRecorder) Save(s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPushRepository)(nil).Save), s)
+}
+
+// MockVerificationMailer is a mock of VerificationMailer interface.
+type MockVerificationMailer struct {
+	ctrl     *gomock.Controller
+	recorder *MockVerificationMailerMockRecorder
+}
+
+// MockVerificationMailerMockRecorder is the mock recorder for MockVerificationMailer.
+type MockVerificationMailerMockRecorder struct {
+	mock *MockVerificationMailer
+}
+
+// NewMockVerificationMailer creates a new mock instance.
+func NewMockVerificationMailer(ctrl *gomock.Controller) *MockVerificationMailer {
+	mock := &MockVerificationMailer{ctrl: ctrl}
+	mock.recorder = &MockVerificationMailerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVerificationMailer) EXPECT() *MockVerificationMailerMockRecorder {
+	return m.recorder
+}
+
+// SendVerification mocks base method.
+func (m *MockVerificationMailer) SendVerification(recipient user.User, link string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendVerification", recipient, link)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendVerification indicates an expected call of SendVerification.
+func (mr *MockVerificationMailerMockRecorder) SendVerification(recipient, link interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendVerification", reflect.TypeOf((*MockVerificationMailer)(nil).SendVerification), recipient, link)
+}
+
+// MockRefreshTokenRepository is a mock of RefreshTokenRepository interface.
+type MockRefreshTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenRepositoryMockRecorder
+}
+
+// MockRefreshTokenRepositoryMockRecorder is the mock recorder for MockRefreshTokenRepository.
+type MockRefreshTokenRepositoryMockRecorder struct {
+	mock *MockRefreshTokenRepository
+}
+
+// NewMockRefreshTokenRepository creates a new mock instance.
+func NewMockRefreshTokenRepository(ctrl *gomock.Controller) *MockRefreshTokenRepository {
+	mock := &MockRefreshTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshTokenRepository) EXPECT() *MockRefreshTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRefreshTokenRepository) Create(t *refresh.Token) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRefreshTokenRepositoryMockRecorder) Create(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRefreshTokenRepository)(nil).Create), t)
+}
+
+// GetByHash mocks base method.
+func (m *MockRefreshTokenRepository) GetByHash(hash string) (*refresh.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", hash)
+	ret0, _ := ret[0].(*refresh.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockRefreshTokenRepositoryMockRecorder) GetByHash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockRefreshTokenRepository)(nil).GetByHash), hash)
+}
+
+// MarkUsed mocks base method.
+func (m *MockRefreshTokenRepository) MarkUsed(id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUsed", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkUsed indicates an expected call of MarkUsed.
+func (mr *MockRefreshTokenRepositoryMockRecorder) MarkUsed(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUsed", reflect.TypeOf((*MockRefreshTokenRepository)(nil).MarkUsed), id)
+}
+
+// RevokeFamily mocks base method.
+func (m *MockRefreshTokenRepository) RevokeFamily(familyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockRefreshTokenRepositoryMockRecorder) RevokeFamily(familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockRefreshTokenRepository)(nil).RevokeFamily), familyID)
+}
+
+// RevokeUser mocks base method.
+func (m *MockRefreshTokenRepository) RevokeUser(userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUser", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUser indicates an expected call of RevokeUser.
+func (mr *MockRefreshTokenRepositoryMockRecorder) RevokeUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUser", reflect.TypeOf((*MockRefreshTokenRepository)(nil).RevokeUser), userID)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"birthdayReminder/internal/handler/preferences"
+	"birthdayReminder/internal/notifier"
+	"birthdayReminder/internal/repository/user"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+)
+
+func validHour(hour int) bool {
+	return hour >= 0 && hour <= 23
+}
+
+// validateQuietHours не даёт выбрать час доставки внутри периода тишины: иначе напоминания
+// откладывались бы на следующий день и терялись.
+func validateQuietHours(u *user.User) error {
+	if !validHour(u.QuietStart) || !validHour(u.QuietEnd) {
+		return errors.New("quiet hours must be between 0 and 23")
+	}
+	if u.InQuietHours(u.NotifyHour) {
+		return errors.New("delivery hour must be outside quiet hours")
+	}
+	return nil
+}
+
+func preferencesResponse(u *user.User) preferences.ResponseDto {
+	dto := preferences.ResponseDto{
+		Channels:     make(map[string]bool),
+		DeliveryHour: u.NotifyHour,
+		DeliveryMode: u.DeliveryMode,
+		Language:     u.Locale,
+		TimeZone:     u.TimeZone,
+	}
+	for _, channel := range notifier.Channels() {
+		dto.Channels[channel] = u.ChannelEnabled(channel)
+	}
+	if u.HasQuietHours() {
+		dto.QuietHours = &preferences.QuietHoursDto{Start: u.QuietStart, End: u.QuietEnd}
+	}
+	return dto
+}
+
+func (h *Handler) writePreferences(w http.ResponseWriter, u *user.User) {
+	response, err := json.Marshal(preferencesResponse(u))
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// GetPreferences /api/me/preferences
+func (h *Handler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling get preferences request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	h.writePreferences(w, dbUser)
+}
+
+// UpdatePreferences /api/me/preferences
+func (h *Handler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling update preferences request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var reqBody preferences.UpdateRequestDto
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		log.Println("Error decoding request body:", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing request body: %v", err)
+		}
+	}(r.Body)
+
+	if len(reqBody.Channels) > 0 {
+		known := make(map[string]bool)
+		for _, channel := range notifier.Channels() {
+			known[channel] = true
+		}
+		for channel := range reqBody.Channels {
+			if !known[channel] {
+				http.Error(w, "Unknown channel: "+channel, http.StatusBadRequest)
+				return
+			}
+		}
+
+		disabled := make([]string, 0)
+		for _, channel := range notifier.Channels() {
+			enabled, ok := reqBody.Channels[channel]
+			if !ok {
+				enabled = dbUser.ChannelEnabled(channel)
+			}
+			if !enabled {
+				disabled = append(disabled, channel)
+			}
+		}
+		dbUser.DisabledChannels = disabled
+	}
+	if reqBody.DeliveryHour != nil {
+		if !validHour(*reqBody.DeliveryHour) {
+			http.Error(w, "Delivery hour must be between 0 and 23", http.StatusBadRequest)
+			return
+		}
+		dbUser.NotifyHour = *reqBody.DeliveryHour
+	}
+	if reqBody.QuietHours != nil {
+		dbUser.QuietStart, dbUser.QuietEnd = reqBody.QuietHours.Start, reqBody.QuietHours.End
+	}
+	if reqBody.DeliveryMode != nil {
+		if !validDeliveryMode(*reqBody.DeliveryMode) {
+			http.Error(w, "Invalid delivery mode", http.StatusBadRequest)
+			return
+		}
+		dbUser.DeliveryMode = *reqBody.DeliveryMode
+	}
+	if reqBody.Language != nil {
+		if !notifier.SupportedLocale(*reqBody.Language) {
+			http.Error(w, "Unsupported language", http.StatusBadRequest)
+			return
+		}
+		dbUser.Locale = *reqBody.Language
+	}
+	if err := validateQuietHours(dbUser); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userRepo.UpdatePreferences(dbUser); err != nil {
+		log.Println("Error updating preferences:", err)
+		http.Error(w, "Error updating preferences", http.StatusInternalServerError)
+		return
+	}
+
+	h.writePreferences(w, dbUser)
+}
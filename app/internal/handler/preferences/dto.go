@@ -0,0 +1,27 @@
+package preferences
+
+// QuietHoursDto - период тишины по местному времени пользователя, часы [start, end).
+// Одинаковые start и end отключают период тишины.
+type QuietHoursDto struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type ResponseDto struct {
+	// Channels - каналы доставки и включены ли они.
+	Channels     map[string]bool `json:"channels"`
+	DeliveryHour int             `json:"delivery_hour"`
+	QuietHours   *QuietHoursDto  `json:"quiet_hours"`
+	DeliveryMode string          `json:"delivery_mode"`
+	Language     string          `json:"language"`
+	TimeZone     string          `json:"time_zone"`
+}
+
+// UpdateRequestDto - частичное обновление, не переданные поля и каналы не меняются.
+type UpdateRequestDto struct {
+	Channels     map[string]bool `json:"channels"`
+	DeliveryHour *int            `json:"delivery_hour"`
+	QuietHours   *QuietHoursDto  `json:"quiet_hours"`
+	DeliveryMode *string         `json:"delivery_mode"`
+	Language     *string         `json:"language"`
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"birthdayReminder/internal/birthday"
+	"birthdayReminder/internal/handler/profile"
+	"birthdayReminder/internal/notifier"
+	"birthdayReminder/internal/repository/user"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+func profileResponse(u *user.User) profile.ResponseDto {
+	return profile.ResponseDto{
+		ID:             u.ID,
+		Name:           u.Name,
+		Email:          u.Email,
+		DateOfBirth:    u.DateOfBirth,
+		TimeZone:       u.TimeZone,
+		NotifyHour:     u.NotifyHour,
+		LeapPolicy:     u.LeapPolicy,
+		Locale:         u.Locale,
+		DeliveryMode:   u.DeliveryMode,
+		WeeklySummary:  u.WeeklySummary,
+		MonthlySummary: u.MonthlySummary,
+		EmailVerified:  u.EmailVerified,
+	}
+}
+
+func validDeliveryMode(mode string) bool {
+	return mode == user.DeliveryIndividual || mode == user.DeliveryDigest
+}
+
+// GetProfile /api/me
+func (h *Handler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	dbUser, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		log.Println("Error fetching user:", err)
+		http.Error(w, "Error fetching profile", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := json.Marshal(profileResponse(dbUser))
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// UpdateProfile /api/me
+func (h *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling update profile request")
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var reqBody profile.UpdateRequestDto
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		log.Println("Error decoding request body:", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing request body: %v", err)
+		}
+	}(r.Body)
+
+	dbUser, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		log.Println("Error fetching user:", err)
+		http.Error(w, "Error fetching profile", http.StatusInternalServerError)
+		return
+	}
+
+	if reqBody.Name != nil {
+		if *reqBody.Name == "" {
+			http.Error(w, "Invalid user data", http.StatusBadRequest)
+			return
+		}
+		dbUser.Name = *reqBody.Name
+	}
+	if reqBody.DateOfBirth != nil {
+		if reqBody.DateOfBirth.IsZero() {
+			http.Error(w, "Invalid user data", http.StatusBadRequest)
+			return
+		}
+		dbUser.DateOfBirth = *reqBody.DateOfBirth
+	}
+	if reqBody.TimeZone != nil {
+		if _, err := time.LoadLocation(*reqBody.TimeZone); err != nil || *reqBody.TimeZone == "" {
+			http.Error(w, "Invalid time zone", http.StatusBadRequest)
+			return
+		}
+		dbUser.TimeZone = *reqBody.TimeZone
+	}
+	if reqBody.NotifyHour != nil {
+		if !validHour(*reqBody.NotifyHour) {
+			http.Error(w, "Notify hour must be between 0 and 23", http.StatusBadRequest)
+			return
+		}
+		dbUser.NotifyHour = *reqBody.NotifyHour
+		if err := validateQuietHours(dbUser); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if reqBody.LeapPolicy != nil {
+		if !birthday.Policy(*reqBody.LeapPolicy).Valid() {
+			http.Error(w, "Invalid leap policy", http.StatusBadRequest)
+			return
+		}
+		dbUser.LeapPolicy = *reqBody.LeapPolicy
+	}
+	if reqBody.Locale != nil {
+		if !notifier.SupportedLocale(*reqBody.Locale) {
+			http.Error(w, "Unsupported locale", http.StatusBadRequest)
+			return
+		}
+		dbUser.Locale = *reqBody.Locale
+	}
+	if reqBody.DeliveryMode != nil {
+		if !validDeliveryMode(*reqBody.DeliveryMode) {
+			http.Error(w, "Invalid delivery mode", http.StatusBadRequest)
+			return
+		}
+		dbUser.DeliveryMode = *reqBody.DeliveryMode
+	}
+	if reqBody.WeeklySummary != nil {
+		dbUser.WeeklySummary = *reqBody.WeeklySummary
+	}
+	if reqBody.MonthlySummary != nil {
+		dbUser.MonthlySummary = *reqBody.MonthlySummary
+	}
+
+	if err := h.userRepo.UpdateProfile(dbUser); err != nil {
+		log.Println("Error updating profile:", err)
+		http.Error(w, "Error updating profile", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := json.Marshal(profileResponse(dbUser))
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
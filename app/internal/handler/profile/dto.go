@@ -0,0 +1,31 @@
+package profile
+
+import "time"
+
+type ResponseDto struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	Email          string    `json:"email"`
+	DateOfBirth    time.Time `json:"date_of_birth"`
+	TimeZone       string    `json:"time_zone"`
+	NotifyHour     int       `json:"notify_hour"`
+	LeapPolicy     string    `json:"leap_policy"`
+	Locale         string    `json:"locale"`
+	DeliveryMode   string    `json:"delivery_mode"`
+	WeeklySummary  bool      `json:"weekly_summary"`
+	MonthlySummary bool      `json:"monthly_summary"`
+	EmailVerified  bool      `json:"email_verified"`
+}
+
+// UpdateRequestDto - частичное обновление профиля, не переданные поля не меняются.
+type UpdateRequestDto struct {
+	Name           *string    `json:"name"`
+	DateOfBirth    *time.Time `json:"date_of_birth"`
+	TimeZone       *string    `json:"time_zone"`
+	NotifyHour     *int       `json:"notify_hour"`
+	LeapPolicy     *string    `json:"leap_policy"`
+	Locale         *string    `json:"locale"`
+	DeliveryMode   *string    `json:"delivery_mode"`
+	WeeklySummary  *bool      `json:"weekly_summary"`
+	MonthlySummary *bool      `json:"monthly_summary"`
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"birthdayReminder/internal/handler/push_subscription"
+	"birthdayReminder/internal/repository/push"
+	"birthdayReminder/internal/webpush"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const maxDeviceNameLength = 255
+
+func pushSubscriptionResponse(s push.Subscription) push_subscription.ResponseDto {
+	return push_subscription.ResponseDto{ID: s.ID, Endpoint: s.Endpoint, Device: s.Device, CreatedAt: s.CreatedAt}
+}
+
+// GetVAPIDKey /api/push/vapid-key
+func (h *Handler) GetVAPIDKey(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling get VAPID key request")
+
+	if h.VAPIDPublicKey == "" {
+		http.Error(w, "Web Push is not configured", http.StatusNotFound)
+		return
+	}
+
+	response, err := json.Marshal(push_subscription.VAPIDKeyDto{PublicKey: h.VAPIDPublicKey})
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// CreatePushSubscription /api/push/subscriptions
+func (h *Handler) CreatePushSubscription(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling create push subscription request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if h.VAPIDPublicKey == "" {
+		http.Error(w, "Web Push is not configured", http.StatusNotFound)
+		return
+	}
+
+	var reqBody push_subscription.RequestDto
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		log.Println("Error decoding request body:", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing request body: %v", err)
+		}
+	}(r.Body)
+
+	if webpush.CheckEndpoint(reqBody.Endpoint, h.AllowPrivateTargets) != nil {
+		http.Error(w, "Invalid push endpoint", http.StatusBadRequest)
+		return
+	}
+	if err := webpush.ValidateKeys(reqBody.Keys.P256dh, reqBody.Keys.Auth); err != nil {
+		http.Error(w, "Invalid subscription keys", http.StatusBadRequest)
+		return
+	}
+	device := strings.TrimSpace(reqBody.Device)
+	if len(device) > maxDeviceNameLength {
+		http.Error(w, "Device name is too long", http.StatusBadRequest)
+		return
+	}
+
+	sub := push.Subscription{
+		UserID:   dbUser.ID,
+		Endpoint: reqBody.Endpoint,
+		P256dh:   reqBody.Keys.P256dh,
+		Auth:     reqBody.Keys.Auth,
+		Device:   device,
+	}
+	if err := h.pushRepo.Save(&sub); err != nil {
+		log.Println("Error saving push subscription:", err)
+		http.Error(w, "Error saving push subscription", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := json.Marshal(pushSubscriptionResponse(sub))
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// GetPushSubscriptions /api/push/subscriptions
+func (h *Handler) GetPushSubscriptions(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling get push subscriptions request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.pushRepo.GetByUser(dbUser.ID)
+	if err != nil {
+		log.Println("Error fetching push subscriptions:", err)
+		http.Error(w, "Error fetching push subscriptions", http.StatusInternalServerError)
+		return
+	}
+
+	dtos := make([]push_subscription.ResponseDto, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		dtos = append(dtos, pushSubscriptionResponse(s))
+	}
+
+	response, err := json.Marshal(dtos)
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// DeletePushSubscription /api/push/subscriptions/{id}
+func (h *Handler) DeletePushSubscription(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling delete push subscription request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid push subscription ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.pushRepo.Delete(dbUser.ID, id); err != nil {
+		if errors.Is(err, push.ErrNotFound) {
+			http.Error(w, "Push subscription not found", http.StatusNotFound)
+		} else {
+			log.Println("Error deleting push subscription:", err)
+			http.Error(w, "Error deleting push subscription", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte("Push subscription deleted"))
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
@@ -0,0 +1,24 @@
+package push_subscription
+
+import "time"
+
+// RequestDto совпадает с PushSubscription.toJSON() браузера, Device - необязательная подпись устройства.
+type RequestDto struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+	Device string `json:"device"`
+}
+
+type ResponseDto struct {
+	ID        int       `json:"id"`
+	Endpoint  string    `json:"endpoint"`
+	Device    string    `json:"device"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type VAPIDKeyDto struct {
+	PublicKey string `json:"public_key"`
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"birthdayReminder/internal/stream"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// streamHeartbeat - период комментариев-пингов, чтобы прокси не закрывали простаивающее соединение.
+// На каждом пинге токен проверяется заново: поток закрывается после выхода из системы.
+var streamHeartbeat = 25 * time.Second
+
+type subscriptionEvent struct {
+	SubscriberID int    `json:"subscriber_id"`
+	Action       string `json:"action"`
+}
+
+// publishSubscriptionEvent сообщает пользователю userID, что на него подписались или отписались.
+func (h *Handler) publishSubscriptionEvent(userID, subscriberID int, action string) {
+	if h.events == nil {
+		return
+	}
+
+	data, err := json.Marshal(subscriptionEvent{SubscriberID: subscriberID, Action: action})
+	if err == nil {
+		err = h.events.Publish(stream.Event{Type: stream.EventSubscription, UserID: userID, Data: data})
+	}
+	if err != nil {
+		log.Println("Error publishing subscription event:", err)
+	}
+}
+
+// StreamNotifications /api/notifications/stream
+func (h *Handler) StreamNotifications(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling notifications stream request")
+
+	// EventSource в браузере не умеет передавать заголовки, поэтому Authenticate принимает и cookie после /api/login
+	claims, ok := h.authenticatedClaims(w, r)
+	if !ok {
+		return
+	}
+	userID := claims.UserID
+	token, _ := tokenFromRequest(r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := h.events.Subscribe(userID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	// поток не должен пережить токен, которым он открыт
+	var expired <-chan time.Time
+	if claims.ExpiresAt != nil {
+		expiry := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-expired:
+			return
+		case <-heartbeat.C:
+			// ParseJWT проверяет и срок действия, и отзыв токена через /api/logout
+			if _, err := h.tokenManager.ParseJWT(token); err != nil {
+				return
+			}
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			data := e.Data
+			if data == nil {
+				data = json.RawMessage("{}")
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				log.Printf("Error writing stream event: %v", err)
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
@@ -1,5 +1,28 @@
 package subscribe
 
 type RequestDto struct {
-	RelatedUserID int `json:"related_user_id"`
+	RelatedUserID int   `json:"related_user_id"`
+	Offsets       []int `json:"offsets"`
+}
+
+type UpdateRequestDto struct {
+	Offsets []int `json:"offsets"`
+}
+
+type ResponseDto struct {
+	ID            int     `json:"id"`
+	RelatedUserID int     `json:"related_user_id"`
+	Offsets       []int   `json:"offsets"`
+	SnoozedUntil  *string `json:"snoozed_until"`
+}
+
+// SnoozeRequestDto - либо дата возобновления напоминаний (YYYY-MM-DD), либо пропуск ближайшего дня рождения.
+type SnoozeRequestDto struct {
+	Until    string `json:"until"`
+	SkipNext bool   `json:"skip_next"`
+}
+
+type SnoozeResponseDto struct {
+	ID           int    `json:"id"`
+	SnoozedUntil string `json:"snoozed_until"`
 }
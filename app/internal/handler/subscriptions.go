@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"birthdayReminder/internal/birthday"
+	"birthdayReminder/internal/handler/subscribe"
+	"birthdayReminder/internal/repository/subscription"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	maxOffsetDays = 60
+	maxOffsets    = 5
+
+	// maxSnoozeDays - пауза дольше года равносильна отписке.
+	maxSnoozeDays = 366
+	dateLayout    = "2006-01-02"
+)
+
+// normalizeOffsets проверяет сроки напоминаний, убирает дубли и сортирует по убыванию.
+// Пустой список означает напоминание за день.
+func normalizeOffsets(offsets []int) ([]int, error) {
+	if len(offsets) == 0 {
+		return []int{1}, nil
+	}
+
+	seen := make(map[int]bool, len(offsets))
+	normalized := make([]int, 0, len(offsets))
+	for _, offset := range offsets {
+		if offset < 0 || offset > maxOffsetDays {
+			return nil, fmt.Errorf("offsets must be between 0 and %d days", maxOffsetDays)
+		}
+		if seen[offset] {
+			continue
+		}
+		seen[offset] = true
+		normalized = append(normalized, offset)
+	}
+
+	if len(normalized) > maxOffsets {
+		return nil, fmt.Errorf("at most %d offsets are allowed", maxOffsets)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(normalized)))
+	return normalized, nil
+}
+
+// GetSubscriptions /api/subscriptions
+func (h *Handler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling get subscriptions request")
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.subscriptionRepo.GetSubscriptions(userID)
+	if err != nil {
+		log.Println("Error fetching subscriptions:", err)
+		http.Error(w, "Error fetching subscriptions", http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]subscribe.ResponseDto, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		dto := subscribe.ResponseDto{
+			ID:            s.ID,
+			RelatedUserID: s.RelatedUserID,
+			Offsets:       s.Offsets,
+		}
+		if s.SnoozedUntil != nil {
+			until := s.SnoozedUntil.Format(dateLayout)
+			dto.SnoozedUntil = &until
+		}
+		result = append(result, dto)
+	}
+
+	response, err := json.Marshal(result)
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// UpdateSubscription /api/subscriptions/{id}
+func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling update subscription request")
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid subscription ID", http.StatusBadRequest)
+		return
+	}
+
+	var reqBody subscribe.UpdateRequestDto
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		log.Println("Error decoding request body:", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing request body: %v", err)
+		}
+	}(r.Body)
+
+	offsets, err := normalizeOffsets(reqBody.Offsets)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.subscriptionRepo.UpdateOffsets(id, userID, offsets); err != nil {
+		if errors.Is(err, subscription.ErrNotFound) {
+			http.Error(w, "Subscription not found", http.StatusNotFound)
+		} else {
+			log.Println("Error updating subscription:", err)
+			http.Error(w, "Error updating subscription", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte("Subscription updated successfully"))
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// parseSnoozeUntil проверяет дату возобновления напоминаний; today - сегодня в поясе подписчика.
+func parseSnoozeUntil(raw string, today time.Time) (time.Time, error) {
+	until, err := time.ParseInLocation(dateLayout, raw, today.Location())
+	if err != nil {
+		return time.Time{}, errors.New("until must be a date in YYYY-MM-DD format")
+	}
+	if !until.After(today) {
+		return time.Time{}, errors.New("until must be in the future")
+	}
+	if until.After(today.AddDate(0, 0, maxSnoozeDays)) {
+		return time.Time{}, fmt.Errorf("until must be within %d days", maxSnoozeDays)
+	}
+	return until, nil
+}
+
+// SnoozeSubscription /api/subscriptions/{id}/snooze
+func (h *Handler) SnoozeSubscription(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling snooze subscription request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid subscription ID", http.StatusBadRequest)
+		return
+	}
+
+	var reqBody subscribe.SnoozeRequestDto
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		log.Println("Error decoding request body:", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing request body: %v", err)
+		}
+	}(r.Body)
+
+	if reqBody.SkipNext == (reqBody.Until != "") {
+		http.Error(w, "Specify either until or skip_next", http.StatusBadRequest)
+		return
+	}
+
+	s, err := h.subscriptionRepo.GetSubscription(id, dbUser.ID)
+	if err != nil {
+		if errors.Is(err, subscription.ErrNotFound) {
+			http.Error(w, "Subscription not found", http.StatusNotFound)
+		} else {
+			log.Println("Error fetching subscription:", err)
+			http.Error(w, "Error fetching subscription", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// "Сегодня" считаем в часовом поясе подписчика, как и рассыльщик
+	loc, err := time.LoadLocation(dbUser.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	var until time.Time
+	if reqBody.SkipNext {
+		// Пропуск ближайшего дня рождения: напоминания возобновятся на следующий день после него
+		celebrant, err := h.userRepo.GetUserByID(s.RelatedUserID)
+		if err != nil {
+			log.Println("Error fetching celebrant:", err)
+			http.Error(w, "Error snoozing subscription", http.StatusInternalServerError)
+			return
+		}
+		until = birthday.Next(celebrant.DateOfBirth, today, birthday.Policy(celebrant.LeapPolicy)).AddDate(0, 0, 1)
+	} else {
+		until, err = parseSnoozeUntil(reqBody.Until, today)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	until = time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, time.UTC)
+	if err := h.subscriptionRepo.Snooze(id, dbUser.ID, &until); err != nil {
+		if errors.Is(err, subscription.ErrNotFound) {
+			http.Error(w, "Subscription not found", http.StatusNotFound)
+		} else {
+			log.Println("Error snoozing subscription:", err)
+			http.Error(w, "Error snoozing subscription", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response, err := json.Marshal(subscribe.SnoozeResponseDto{ID: id, SnoozedUntil: until.Format(dateLayout)})
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// UnsnoozeSubscription /api/subscriptions/{id}/snooze
+func (h *Handler) UnsnoozeSubscription(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling unsnooze subscription request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid subscription ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.subscriptionRepo.Snooze(id, dbUser.ID, nil); err != nil {
+		if errors.Is(err, subscription.ErrNotFound) {
+			http.Error(w, "Subscription not found", http.StatusNotFound)
+		} else {
+			log.Println("Error unsnoozing subscription:", err)
+			http.Error(w, "Error unsnoozing subscription", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte("Subscription resumed"))
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
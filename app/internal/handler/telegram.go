@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"birthdayReminder/internal/handler/telegram_link"
+	"birthdayReminder/internal/repository/telegram"
+	"crypto/rand"
+	"encoding/json"
+	"log"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+const (
+	linkCodeLength = 8
+	linkCodeTTL    = 15 * time.Minute
+	// без похожих друг на друга символов (0/O, 1/I)
+	linkCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+func generateLinkCode() (string, error) {
+	code := make([]byte, linkCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(linkCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = linkCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// CreateTelegramLink /api/telegram/link
+func (h *Handler) CreateTelegramLink(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling create telegram link request")
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	code, err := generateLinkCode()
+	if err != nil {
+		log.Println("Error generating link code:", err)
+		http.Error(w, "Error generating link code", http.StatusInternalServerError)
+		return
+	}
+
+	linkCode := telegram.LinkCode{Code: code, UserID: userID, ExpiresAt: time.Now().Add(linkCodeTTL)}
+	if err := h.telegramRepo.CreateLinkCode(linkCode); err != nil {
+		log.Println("Error saving link code:", err)
+		http.Error(w, "Error saving link code", http.StatusInternalServerError)
+		return
+	}
+
+	dto := telegram_link.ResponseDto{Code: linkCode.Code, ExpiresAt: linkCode.ExpiresAt}
+	if h.TelegramBotName != "" {
+		dto.Link = "https://t.me/" + h.TelegramBotName + "?start=" + linkCode.Code
+	}
+
+	response, err := json.Marshal(dto)
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// DeleteTelegramLink /api/telegram/link
+func (h *Handler) DeleteTelegramLink(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling delete telegram link request")
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.telegramRepo.Unlink(userID); err != nil {
+		log.Println("Error unlinking telegram chat:", err)
+		http.Error(w, "Error unlinking telegram chat", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write([]byte("Telegram chat unlinked"))
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
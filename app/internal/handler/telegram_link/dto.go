@@ -0,0 +1,10 @@
+package telegram_link
+
+import "time"
+
+type ResponseDto struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Link - ссылка, открывающая бота с уже подставленным кодом (если задан TELEGRAM_BOT_NAME).
+	Link string `json:"link,omitempty"`
+}
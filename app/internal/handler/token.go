@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"birthdayReminder/internal/handler/auth"
+	"birthdayReminder/internal/handler/login"
+	"birthdayReminder/internal/repository/refresh"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	refreshCookieName = "refresh_token"
+	// refresh-токен нужен только обновлению токена и выходу, страницам сайта cookie не отправляется
+	refreshCookiePath = "/api"
+)
+
+// refreshTokenFromRequest читает refresh-токен из тела запроса (может быть пустым) или из cookie.
+func refreshTokenFromRequest(r *http.Request) (string, error) {
+	var reqBody login.RefreshRequestDto
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing request body: %v", err)
+		}
+	}(r.Body)
+
+	if reqBody.RefreshToken != "" {
+		return reqBody.RefreshToken, nil
+	}
+	if cookie, err := r.Cookie(refreshCookieName); err == nil {
+		return cookie.Value, nil
+	}
+	return "", nil
+}
+
+// clearAuthCookies удаляет cookie с токенами в браузере.
+func clearAuthCookies(w http.ResponseWriter) {
+	for name, path := range map[string]string{"jwt_token": "/", refreshCookieName: refreshCookiePath} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			MaxAge:   -1,
+			Path:     path,
+			SameSite: http.SameSiteStrictMode,
+			HttpOnly: true,
+			Secure:   true,
+		})
+	}
+}
+
+// issueTokens выдает JWT и новый refresh-токен цепочки familyID, ставит cookie и пишет ответ.
+func (h *Handler) issueTokens(w http.ResponseWriter, userID int, familyID string) {
+	accessToken, err := h.tokenManager.GenerateJWT(userID)
+	if err != nil {
+		http.Error(w, "Failed to generate JWT token", http.StatusInternalServerError)
+		return
+	}
+
+	refreshToken, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		log.Println("Error generating refresh token:", err)
+		http.Error(w, "Failed to generate refresh token", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	stored := refresh.Token{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: now.Add(auth.RefreshTokenTTL),
+	}
+	if err := h.refreshRepo.Create(&stored); err != nil {
+		log.Println("Error saving refresh token:", err)
+		http.Error(w, "Failed to generate refresh token", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     "jwt_token",
+		Value:    accessToken,
+		Expires:  now.Add(auth.AccessTokenTTL),
+		Path:     "/",
+		SameSite: http.SameSiteStrictMode,
+		HttpOnly: true,
+		Secure:   true,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    refreshToken,
+		Expires:  stored.ExpiresAt,
+		Path:     refreshCookiePath,
+		SameSite: http.SameSiteStrictMode,
+		HttpOnly: true,
+		Secure:   true,
+	})
+
+	response, err := json.Marshal(login.ResponseDto{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(auth.AccessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+	})
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// RefreshToken /api/token/refresh
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling token refresh request")
+
+	// браузер присылает токен в cookie, остальные клиенты - в теле запроса
+	refreshToken, err := refreshTokenFromRequest(r)
+	if err != nil {
+		log.Println("Error decoding request body:", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if refreshToken == "" {
+		http.Error(w, "Refresh token missing", http.StatusUnauthorized)
+		return
+	}
+
+	stored, err := h.refreshRepo.GetByHash(auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, refresh.ErrNotFound) {
+			http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		} else {
+			log.Println("Error fetching refresh token:", err)
+			http.Error(w, "Error refreshing token", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	// Повторное предъявление уже обменянного токена значит, что его копия у кого-то еще:
+	// отзываем всю цепочку, и обоим владельцам придется войти заново
+	used := stored.UsedAt != nil
+	if !used {
+		marked, err := h.refreshRepo.MarkUsed(stored.ID)
+		if err != nil {
+			log.Println("Error rotating refresh token:", err)
+			http.Error(w, "Error refreshing token", http.StatusInternalServerError)
+			return
+		}
+		used = !marked
+	}
+	if used {
+		log.Printf("Refresh token reuse detected for user ID %d, revoking family %s\n", stored.UserID, stored.FamilyID)
+		if err := h.refreshRepo.RevokeFamily(stored.FamilyID); err != nil {
+			log.Println("Error revoking refresh token family:", err)
+		}
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	h.issueTokens(w, stored.UserID, stored.FamilyID)
+}
+
+// Logout /api/logout
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling logout request")
+
+	claims, ok := h.authenticatedClaims(w, r)
+	if !ok {
+		return
+	}
+
+	refreshToken, err := refreshTokenFromRequest(r)
+	if err != nil {
+		log.Println("Error decoding request body:", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.tokenManager.RevokeJWT(claims); err != nil {
+		log.Println("Error revoking JWT:", err)
+		http.Error(w, "Error logging out", http.StatusInternalServerError)
+		return
+	}
+
+	// Вместе с JWT закрываем цепочку refresh-токенов этого входа, иначе сессию можно продлить
+	if refreshToken != "" {
+		stored, err := h.refreshRepo.GetByHash(auth.HashRefreshToken(refreshToken))
+		switch {
+		case errors.Is(err, refresh.ErrNotFound):
+		case err != nil:
+			log.Println("Error fetching refresh token:", err)
+			http.Error(w, "Error logging out", http.StatusInternalServerError)
+			return
+		case stored.UserID == claims.UserID:
+			if err := h.refreshRepo.RevokeFamily(stored.FamilyID); err != nil {
+				log.Println("Error revoking refresh token family:", err)
+				http.Error(w, "Error logging out", http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	clearAuthCookies(w)
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte("Logged out"))
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// LogoutAll /api/logout-all
+func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling logout from all devices request")
+
+	claims, ok := h.authenticatedClaims(w, r)
+	if !ok {
+		return
+	}
+	userID := claims.UserID
+
+	// текущий токен отзываем явно, не полагаясь на сравнение времени выдачи
+	if err := h.tokenManager.RevokeJWT(claims); err != nil {
+		log.Println("Error revoking JWT:", err)
+		http.Error(w, "Error logging out", http.StatusInternalServerError)
+		return
+	}
+	if err := h.tokenManager.RevokeAllJWT(userID); err != nil {
+		log.Println("Error revoking user JWTs:", err)
+		http.Error(w, "Error logging out", http.StatusInternalServerError)
+		return
+	}
+	if err := h.refreshRepo.RevokeUser(userID); err != nil {
+		log.Println("Error revoking user refresh tokens:", err)
+		http.Error(w, "Error logging out", http.StatusInternalServerError)
+		return
+	}
+
+	clearAuthCookies(w)
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write([]byte("Logged out on all devices"))
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// GetJWKS /.well-known/jwks.json
+func (h *Handler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling get JWKS request")
+
+	response, err := json.Marshal(h.tokenManager.JWKS())
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	// ключи меняются только при ротации, другим сервисам можно кэшировать ответ
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"birthdayReminder/internal/repository/user"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultBaseURL - адрес для ссылок в письмах, если APP_BASE_URL не задан (локальный запуск).
+// Брать адрес из заголовка Host нельзя: его подменой можно увести токен подтверждения на чужой сайт.
+const defaultBaseURL = "http://localhost:8080"
+
+// verificationResendInterval - не чаще одного письма подтверждения за этот период.
+const verificationResendInterval = 5 * time.Minute
+
+var errVerificationThrottled = errors.New("verification email was sent recently")
+
+// verificationLink собирает ссылку на /api/verify-email с подписанным токеном.
+func (h *Handler) verificationLink(token string) string {
+	return strings.TrimRight(h.BaseURL, "/") + "/api/verify-email?token=" + url.QueryEscape(token)
+}
+
+// sendVerification выпускает новую ссылку подтверждения, которая заменяет прежние, и отправляет ее на адрес пользователя.
+// Если предыдущее письмо отправлено меньше interval назад, возвращает errVerificationThrottled.
+func (h *Handler) sendVerification(u *user.User, interval time.Duration) error {
+	token, tokenID, err := h.tokenManager.GenerateEmailVerificationToken(u.ID, u.Email)
+	if err != nil {
+		return err
+	}
+
+	started, err := h.userRepo.StartVerification(u.ID, tokenID, interval)
+	if err != nil {
+		return fmt.Errorf("error saving verification token: %v", err)
+	}
+	if !started {
+		return errVerificationThrottled
+	}
+
+	return h.mailer.SendVerification(*u, h.verificationLink(token))
+}
+
+// VerifyEmail /api/verify-email
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling verify email request")
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "Verification token missing", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := h.tokenManager.ParseEmailVerificationToken(token)
+	if err != nil {
+		http.Error(w, "Invalid or expired verification link", http.StatusBadRequest)
+		return
+	}
+
+	verified, err := h.userRepo.VerifyEmail(claims.UserID(), claims.Email, claims.ID)
+	if err != nil {
+		log.Println("Error verifying email:", err)
+		http.Error(w, "Error verifying email", http.StatusInternalServerError)
+		return
+	}
+
+	if !verified {
+		// повторный переход по уже использованной ссылке не считается ошибкой
+		dbUser, err := h.userRepo.GetUserByID(claims.UserID())
+		if err != nil || !dbUser.EmailVerified || dbUser.Email != claims.Email {
+			http.Error(w, "Invalid or expired verification link", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte("Email already verified")); err != nil {
+			log.Printf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("Email verified successfully")); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+// ResendVerification /api/verify-email/resend
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling resend verification request")
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	dbUser, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		log.Println("Error fetching user:", err)
+		http.Error(w, "Error fetching user", http.StatusInternalServerError)
+		return
+	}
+
+	if dbUser.EmailVerified {
+		http.Error(w, "Email already verified", http.StatusConflict)
+		return
+	}
+
+	err = h.sendVerification(dbUser, verificationResendInterval)
+	if errors.Is(err, errVerificationThrottled) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(verificationResendInterval.Seconds())))
+		http.Error(w, "Verification email was sent recently, try again later", http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		log.Println("Error sending verification email:", err)
+		http.Error(w, "Error sending verification email", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	if _, err := w.Write([]byte("Verification email sent")); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
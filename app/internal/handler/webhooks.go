@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"birthdayReminder/internal/handler/webhooks"
+	"birthdayReminder/internal/netguard"
+	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/repository/webhook"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+const webhookDeliveriesLimit = 50
+
+// validWebhookURL проверяет адрес вебхука: http(s) и не внутренняя сеть, если allowPrivate не задан.
+func validWebhookURL(raw string, allowPrivate bool) bool {
+	return netguard.CheckURL(raw, allowPrivate, "http", "https") == nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func webhookResponse(w webhook.Webhook) webhooks.ResponseDto {
+	return webhooks.ResponseDto{ID: w.ID, URL: w.URL, IsGlobal: w.IsGlobal, CreatedAt: w.CreatedAt}
+}
+
+// ownedWebhook загружает вебхук из пути запроса, доступный пользователю: свой или, для администратора, глобальный.
+func (h *Handler) ownedWebhook(w http.ResponseWriter, r *http.Request, u *user.User) (*webhook.Webhook, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	hook, err := h.webhookRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			http.Error(w, "Webhook not found", http.StatusNotFound)
+		} else {
+			log.Println("Error fetching webhook:", err)
+			http.Error(w, "Error fetching webhook", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	if hook.UserID != u.ID && !(u.IsAdmin && hook.IsGlobal) {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	return hook, true
+}
+
+// CreateWebhook /api/webhooks
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling create webhook request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var reqBody webhooks.RequestDto
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		log.Println("Error decoding request body:", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing request body: %v", err)
+		}
+	}(r.Body)
+
+	if !validWebhookURL(reqBody.URL, h.AllowPrivateTargets) {
+		http.Error(w, "Invalid webhook URL", http.StatusBadRequest)
+		return
+	}
+
+	if reqBody.IsGlobal && !dbUser.IsAdmin {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		log.Println("Error generating webhook secret:", err)
+		http.Error(w, "Error creating webhook", http.StatusInternalServerError)
+		return
+	}
+
+	hook := webhook.Webhook{UserID: dbUser.ID, URL: reqBody.URL, Secret: secret, IsGlobal: reqBody.IsGlobal}
+	if err := h.webhookRepo.Create(&hook); err != nil {
+		log.Println("Error creating webhook:", err)
+		http.Error(w, "Error creating webhook", http.StatusInternalServerError)
+		return
+	}
+
+	dto := webhookResponse(hook)
+	dto.Secret = hook.Secret
+
+	response, err := json.Marshal(dto)
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// GetWebhooks /api/webhooks
+func (h *Handler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling get webhooks request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	hooks, err := h.webhookRepo.GetVisible(dbUser.ID, dbUser.IsAdmin)
+	if err != nil {
+		log.Println("Error fetching webhooks:", err)
+		http.Error(w, "Error fetching webhooks", http.StatusInternalServerError)
+		return
+	}
+
+	dtos := make([]webhooks.ResponseDto, 0, len(hooks))
+	for _, hook := range hooks {
+		dtos = append(dtos, webhookResponse(hook))
+	}
+
+	response, err := json.Marshal(dtos)
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// DeleteWebhook /api/webhooks/{id}
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling delete webhook request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	hook, ok := h.ownedWebhook(w, r, dbUser)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookRepo.Delete(hook.ID); err != nil && !errors.Is(err, webhook.ErrNotFound) {
+		log.Println("Error deleting webhook:", err)
+		http.Error(w, "Error deleting webhook", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write([]byte("Webhook deleted"))
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
+
+// GetWebhookDeliveries /api/webhooks/{id}/deliveries
+func (h *Handler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	log.Println("Handling get webhook deliveries request")
+
+	dbUser, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	hook, ok := h.ownedWebhook(w, r, dbUser)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.webhookRepo.GetDeliveries(hook.ID, webhookDeliveriesLimit)
+	if err != nil {
+		log.Println("Error fetching webhook deliveries:", err)
+		http.Error(w, "Error fetching webhook deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	dtos := make([]webhooks.DeliveryDto, 0, len(deliveries))
+	for _, d := range deliveries {
+		dtos = append(dtos, webhooks.DeliveryDto{
+			ID:         d.ID,
+			EventID:    d.EventID,
+			EventType:  d.EventType,
+			StatusCode: d.StatusCode,
+			Error:      d.Error,
+			Success:    d.Success,
+			DurationMs: d.DurationMs,
+			CreatedAt:  d.CreatedAt,
+		})
+	}
+
+	response, err := json.Marshal(dtos)
+	if err != nil {
+		log.Println("Error marshalling response:", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		log.Printf("Error writing response: %v", err)
+		return
+	}
+}
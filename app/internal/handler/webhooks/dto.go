@@ -0,0 +1,29 @@
+package webhooks
+
+import "time"
+
+type RequestDto struct {
+	URL string `json:"url"`
+	// IsGlobal - вебхук получает события всех пользователей, доступно только администратору.
+	IsGlobal bool `json:"is_global"`
+}
+
+type ResponseDto struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	IsGlobal  bool      `json:"is_global"`
+	CreatedAt time.Time `json:"created_at"`
+	// Secret возвращается только при создании вебхука.
+	Secret string `json:"secret,omitempty"`
+}
+
+type DeliveryDto struct {
+	ID         int       `json:"id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	StatusCode *int      `json:"status_code"`
+	Error      *string   `json:"error"`
+	Success    bool      `json:"success"`
+	DurationMs int       `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,129 @@
+// Package netguard не дает исходящим запросам на адреса пользователей (вебхуки, push-сервисы)
+// попасть во внутреннюю сеть сервиса.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress - адрес назначения во внутренней сети.
+var ErrForbiddenAddress = errors.New("destination address is not allowed")
+
+// блоки, которые не покрывают методы net.IP: "эта сеть" (0.x в Linux ведет на localhost) и CGNAT
+var forbiddenNets = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// AllowPrivateFromEnv читает OUTBOUND_ALLOW_PRIVATE: true разрешает внутренние адреса и http для push,
+// нужно только для тестов с локальными заглушками.
+func AllowPrivateFromEnv() bool {
+	return os.Getenv("OUTBOUND_ALLOW_PRIVATE") == "true"
+}
+
+// Allowed сообщает, можно ли отправлять запросы на ip: loopback, частные, link-local,
+// multicast и неуказанные адреса запрещены.
+func Allowed(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range forbiddenNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL проверяет адрес, который сохраняет пользователь: схема из schemes, непустой хост,
+// а IP-адрес или localhost в хосте - только при allowPrivate. Имена, которые резолвятся во
+// внутреннюю сеть, отсекает уже клиент из NewHTTPClient при подключении.
+func CheckURL(raw string, allowPrivate bool, schemes ...string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if !schemeAllowed(u.Scheme, schemes) {
+		return fmt.Errorf("scheme %q is not allowed", u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return errors.New("host is missing")
+	}
+	if allowPrivate {
+		return nil
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && !Allowed(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+func schemeAllowed(scheme string, schemes []string) bool {
+	for _, s := range schemes {
+		if strings.EqualFold(scheme, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewHTTPClient - клиент для адресов, заданных пользователями. Адрес проверяется после DNS-резолва,
+// в момент подключения, поэтому его не обойти ни DNS-ребиндингом, ни редиректом: редиректы
+// не выполняются, ответ 3xx возвращается как есть. Прокси из окружения не используется.
+func NewHTTPClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = control
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// control вызывается для уже разрешенного адреса ip:port перед connect.
+func control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !Allowed(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
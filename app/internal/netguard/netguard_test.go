@@ -0,0 +1,63 @@
+package netguard
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAllowed(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "fe80::1", "fc00::1", "0.0.0.0", "::", "0.1.2.3", "100.64.0.1", "224.0.0.1", "::ffff:127.0.0.1"} {
+		assert.False(t, Allowed(net.ParseIP(addr)), addr)
+	}
+	for _, addr := range []string{"8.8.8.8", "93.184.216.34", "2606:4700:4700::1111"} {
+		assert.True(t, Allowed(net.ParseIP(addr)), addr)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	assert.NoError(t, CheckURL("https://example.com/hook", false, "http", "https"))
+	assert.Error(t, CheckURL("ftp://example.com/hook", false, "http", "https"))
+	assert.Error(t, CheckURL("http://example.com/push", false, "https"))
+	assert.Error(t, CheckURL("https:///hook", false, "https"))
+	assert.ErrorIs(t, CheckURL("http://127.0.0.1:5432", false, "http"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL("http://[::1]/", false, "http"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL("http://LOCALHOST./", false, "http"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL("http://api.localhost/", false, "http"), ErrForbiddenAddress)
+	assert.NoError(t, CheckURL("http://127.0.0.1:5432", true, "http"))
+}
+
+func TestHTTPClientBlocksInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// localhost резолвится в loopback, проверка срабатывает уже после DNS
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	_, err = NewHTTPClient(time.Second, false).Get("http://localhost:" + port)
+	assert.True(t, errors.Is(err, ErrForbiddenAddress), "err=%v", err)
+
+	resp, err := NewHTTPClient(time.Second, true).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestHTTPClientDoesNotFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	resp, err := NewHTTPClient(time.Second, true).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+}
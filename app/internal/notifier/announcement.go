@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/birthday"
+	"birthdayReminder/internal/netguard"
+	"birthdayReminder/internal/repository/announcement"
+	"birthdayReminder/internal/repository/user"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	texttemplate "text/template"
+	"time"
+)
+
+// DefaultAnnouncementFormat - формат объявления, если администратор не задал свой.
+const DefaultAnnouncementFormat = `{{if eq .DaysUntil 0}}Сегодня{{else if eq .DaysUntil 1}}Завтра{{else}}Через {{.DaysUntil}} {{plural .DaysUntil "день" "дня" "дней"}}{{end}} день рождения у {{.Name}}! :tada:`
+
+// AnnouncementData - переменные, доступные в формате объявления.
+type AnnouncementData struct {
+	Name      string
+	Age       int
+	DaysUntil int
+	Date      time.Time
+}
+
+// ParseAnnouncementFormat проверяет формат объявления, в том числе пробным выполнением.
+func ParseAnnouncementFormat(format string) (*texttemplate.Template, error) {
+	tmpl, err := texttemplate.New("announcement").Funcs(templateFuncs(user.DefaultLocale)).Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	sample := AnnouncementData{Name: "Anna", Age: 30, DaysUntil: 1, Date: time.Now()}
+	if err := tmpl.Execute(io.Discard, sample); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// ChatWebhookClient публикует сообщения через incoming webhook Slack или Mattermost:
+// оба принимают JSON вида {"text": "..."}.
+type ChatWebhookClient struct {
+	httpClient *http.Client
+}
+
+// NewChatWebhookClient создаёт клиент, который не подключается к внутренним адресам,
+// даже если к ним ведёт редирект или DNS-имя; allowPrivate снимает ограничение для локальной разработки.
+func NewChatWebhookClient(allowPrivate bool) *ChatWebhookClient {
+	return &ChatWebhookClient{httpClient: netguard.NewHTTPClient(10*time.Second, allowPrivate)}
+}
+
+func (c *ChatWebhookClient) Post(url, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// тело ответа не выводится: по нему можно было бы читать ответы внутренних сервисов
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SendAnnouncements публикует объявления о днях рождения в командные чаты.
+// Вызывается из цикла рассылки; каждый чат получает объявление один раз
+// в заданный час по своему часовому поясу, неудачные публикации повторяются следующим циклом.
+func (n *Notifier) SendAnnouncements() {
+	n.sendAnnouncements(time.Now())
+}
+
+func (n *Notifier) sendAnnouncements(now time.Time) {
+	if n.announcementRepo == nil {
+		return
+	}
+
+	channels, err := n.announcementRepo.GetAll()
+	if err != nil {
+		log.Println("Error fetching announcement channels:", err)
+		return
+	}
+
+	for _, channel := range channels {
+		local := now.In(loadLocation(channel.TimeZone))
+		if local.Hour() < channel.PostHour {
+			continue
+		}
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location()).AddDate(0, 0, channel.DaysAhead)
+
+		celebrants, err := n.userRepo.GetUsersWithBirthdayBetween(day, day)
+		if err != nil {
+			log.Printf("Error fetching birthdays for %s: %v\n", day.Format("2006-01-02"), err)
+			continue
+		}
+
+		for _, celebrant := range celebrants {
+			n.announce(channel, celebrant, day)
+		}
+	}
+}
+
+func (n *Notifier) announce(channel announcement.Channel, celebrant user.User, day time.Time) {
+	format := channel.Format
+	if format == "" {
+		format = DefaultAnnouncementFormat
+	}
+	tmpl, err := ParseAnnouncementFormat(format)
+	if err != nil {
+		log.Printf("Invalid format of announcement channel ID %d: %v\n", channel.ID, err)
+		return
+	}
+
+	var text bytes.Buffer
+	err = tmpl.Execute(&text, AnnouncementData{
+		Name:      celebrant.Name,
+		Age:       birthday.Age(celebrant.DateOfBirth, day),
+		DaysUntil: channel.DaysAhead,
+		Date:      day,
+	})
+	if err != nil {
+		log.Printf("Error rendering announcement for channel ID %d: %v\n", channel.ID, err)
+		return
+	}
+
+	claimed, err := n.announcementRepo.Claim(channel.ID, celebrant.ID, day)
+	if err != nil {
+		log.Printf("Error claiming announcement for channel ID %d: %v\n", channel.ID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	if err := n.chatClient.Post(channel.URL, text.String()); err != nil {
+		log.Printf("Error posting announcement to channel ID %d: %v\n", channel.ID, err)
+		if err := n.announcementRepo.Release(channel.ID, celebrant.ID, day); err != nil {
+			log.Printf("Error releasing announcement for channel ID %d: %v\n", channel.ID, err)
+		}
+		return
+	}
+	log.Printf("Announced birthday of user ID %d in channel %q\n", celebrant.ID, channel.Name)
+}
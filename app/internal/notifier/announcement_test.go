@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/netguard"
+	mock_notifier "birthdayReminder/internal/notifier/mocks"
+	"birthdayReminder/internal/repository/announcement"
+	"birthdayReminder/internal/repository/user"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type memoryPoster struct {
+	err   error
+	posts []string
+}
+
+func (p *memoryPoster) Post(url, text string) error {
+	p.posts = append(p.posts, url+" "+text)
+	return p.err
+}
+
+func TestParseAnnouncementFormat(t *testing.T) {
+	_, err := ParseAnnouncementFormat(DefaultAnnouncementFormat)
+	assert.NoError(t, err)
+
+	_, err = ParseAnnouncementFormat("{{.Name")
+	assert.Error(t, err)
+
+	_, err = ParseAnnouncementFormat("{{.Nickname}}")
+	assert.Error(t, err)
+}
+
+func TestChatWebhookClient(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		if payload["text"] == "fail" {
+			http.Error(w, "invalid_payload", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	// тестовый сервер слушает 127.0.0.1
+	client := NewChatWebhookClient(true)
+	require.NoError(t, client.Post(server.URL, "hello"))
+	assert.Equal(t, "hello", payload["text"])
+
+	err := client.Post(server.URL, "fail")
+	require.Error(t, err)
+	assert.Equal(t, "unexpected status 400", err.Error())
+}
+
+func TestChatWebhookClientRefusesInternalTargets(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	err := NewChatWebhookClient(false).Post(server.URL, "hello")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, netguard.ErrForbiddenAddress)
+	assert.False(t, called)
+}
+
+func TestSendAnnouncements(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 9, 30, 0, 0, time.UTC)
+	anna := user.User{ID: 1, Name: "Anna", DateOfBirth: time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC)}
+	channels := []announcement.Channel{
+		{ID: 1, Name: "team", URL: "https://chat/1", Format: "{{.Name}} {{.Age}} {{.DaysUntil}}", DaysAhead: 1, TimeZone: "UTC", PostHour: 9},
+		{ID: 2, Name: "later", URL: "https://chat/2", DaysAhead: 1, TimeZone: "UTC", PostHour: 18},
+	}
+
+	testCases := []struct {
+		name          string
+		postErr       error
+		claimed       bool
+		expectRelease bool
+		expectedPosts []string
+	}{
+		{
+			name:          "Posted once per claimed channel",
+			claimed:       true,
+			expectedPosts: []string{"https://chat/1 Anna 36 1"},
+		},
+		{
+			name:    "Already announced",
+			claimed: false,
+		},
+		{
+			name:          "Failed post releases claim",
+			claimed:       true,
+			postErr:       errors.New("chat down"),
+			expectRelease: true,
+			expectedPosts: []string{"https://chat/1 Anna 36 1"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_notifier.NewMockUserRepository(ctrl)
+			mockAnnouncementRepo := mock_notifier.NewMockAnnouncementRepository(ctrl)
+			day := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+			mockAnnouncementRepo.EXPECT().GetAll().Return(channels, nil)
+			mockUserRepo.EXPECT().GetUsersWithBirthdayBetween(day, day).Return([]user.User{anna}, nil)
+			mockAnnouncementRepo.EXPECT().Claim(1, 1, day).Return(tt.claimed, nil)
+			if tt.expectRelease {
+				mockAnnouncementRepo.EXPECT().Release(1, 1, day).Return(nil)
+			}
+
+			poster := &memoryPoster{err: tt.postErr}
+			n := New(mockUserRepo, nil, nil, mockAnnouncementRepo, NewRegistry(), nil, DefaultConfig())
+			n.chatClient = poster
+
+			n.sendAnnouncements(now)
+
+			assert.Equal(t, tt.expectedPosts, poster.posts)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/netguard"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config задает расписание рассылки.
+type Config struct {
+	// Schedule - cron-выражение запуска цикла рассылки.
+	Schedule string
+	// TimeZone - часовой пояс, в котором интерпретируется Schedule.
+	TimeZone string
+	// RunOnStartup запускает цикл рассылки сразу после старта планировщика.
+	RunOnStartup bool
+	// RetryInterval - период обработки очереди повторных отправок.
+	RetryInterval time.Duration
+	// SummarySchedule - cron-выражение запуска рассылки недельных и месячных сводок.
+	SummarySchedule string
+	// AllowPrivateTargets разрешает публиковать объявления на внутренние адреса (OUTBOUND_ALLOW_PRIVATE).
+	AllowPrivateTargets bool
+}
+
+// Напоминания отправляются в выбранный подписчиком час, поэтому по умолчанию цикл запускается ежечасно.
+func DefaultConfig() Config {
+	return Config{
+		Schedule:        "0 * * * *",
+		TimeZone:        "UTC",
+		RunOnStartup:    true,
+		RetryInterval:   time.Minute,
+		SummarySchedule: "0 * * * *",
+	}
+}
+
+// ConfigFromEnv читает настройки из NOTIFIER_SCHEDULE, NOTIFIER_TIME_ZONE, NOTIFIER_RUN_ON_STARTUP,
+// NOTIFIER_RETRY_INTERVAL, NOTIFIER_SUMMARY_SCHEDULE и OUTBOUND_ALLOW_PRIVATE; незаданные берутся из DefaultConfig.
+func ConfigFromEnv() (Config, error) {
+	config := DefaultConfig()
+
+	if schedule := os.Getenv("NOTIFIER_SCHEDULE"); schedule != "" {
+		config.Schedule = schedule
+	}
+
+	if timeZone := os.Getenv("NOTIFIER_TIME_ZONE"); timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return Config{}, fmt.Errorf("invalid NOTIFIER_TIME_ZONE: %v", err)
+		}
+		config.TimeZone = timeZone
+	}
+
+	if runOnStartup := os.Getenv("NOTIFIER_RUN_ON_STARTUP"); runOnStartup != "" {
+		value, err := strconv.ParseBool(runOnStartup)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid NOTIFIER_RUN_ON_STARTUP: %v", err)
+		}
+		config.RunOnStartup = value
+	}
+
+	if retryInterval := os.Getenv("NOTIFIER_RETRY_INTERVAL"); retryInterval != "" {
+		value, err := time.ParseDuration(retryInterval)
+		if err != nil || value <= 0 {
+			return Config{}, fmt.Errorf("invalid NOTIFIER_RETRY_INTERVAL: %q", retryInterval)
+		}
+		config.RetryInterval = value
+	}
+
+	if summarySchedule := os.Getenv("NOTIFIER_SUMMARY_SCHEDULE"); summarySchedule != "" {
+		config.SummarySchedule = summarySchedule
+	}
+
+	config.AllowPrivateTargets = netguard.AllowPrivateFromEnv()
+
+	return config, nil
+}
+
+const (
+	MailTransportSMTP    = "smtp"
+	MailTransportFile    = "file"
+	MailTransportMaildir = "maildir"
+
+	SMTPAuthPlain   = "plain"
+	SMTPAuthLogin   = "login"
+	SMTPAuthCRAMMD5 = "crammd5"
+	SMTPAuthNone    = "none"
+
+	// SMTPTLSStartTLS - обычное соединение с обязательным STARTTLS, SMTPTLSImplicit - TLS с первого байта (порт 465).
+	SMTPTLSStartTLS = "starttls"
+	SMTPTLSImplicit = "tls"
+	SMTPTLSNone     = "none"
+)
+
+// MailConfig задает способ доставки писем.
+type MailConfig struct {
+	// Transport - smtp, file (.eml файлы в Dir) или maildir (Maildir в Dir).
+	Transport string
+	Host      string
+	Port      string
+	// From - адрес отправителя; по умолчанию совпадает с Username.
+	From     string
+	Username string
+	Password string
+	AuthMode string
+	TLSMode  string
+	Dir      string
+	Timeout  time.Duration
+}
+
+func DefaultMailConfig() MailConfig {
+	return MailConfig{
+		Transport: MailTransportSMTP,
+		Host:      "smtp.mail.ru",
+		Port:      "587",
+		AuthMode:  SMTPAuthPlain,
+		TLSMode:   SMTPTLSStartTLS,
+		Dir:       "mail",
+		Timeout:   30 * time.Second,
+	}
+}
+
+// MailConfigFromEnv читает MAIL_TRANSPORT, MAIL_DIR, SMTP_HOST, SMTP_PORT, SMTP_FROM,
+// SMTP_USERNAME, SMTP_PASSWORD, SMTP_AUTH и SMTP_TLS; незаданные берутся из DefaultMailConfig.
+func MailConfigFromEnv() (MailConfig, error) {
+	config := DefaultMailConfig()
+
+	if transport := os.Getenv("MAIL_TRANSPORT"); transport != "" {
+		config.Transport = transport
+	}
+	switch config.Transport {
+	case MailTransportSMTP, MailTransportFile, MailTransportMaildir:
+	default:
+		return MailConfig{}, fmt.Errorf("invalid MAIL_TRANSPORT: %q", config.Transport)
+	}
+
+	if dir := os.Getenv("MAIL_DIR"); dir != "" {
+		config.Dir = dir
+	}
+
+	if tlsMode := os.Getenv("SMTP_TLS"); tlsMode != "" {
+		config.TLSMode = tlsMode
+	}
+	switch config.TLSMode {
+	case SMTPTLSStartTLS, SMTPTLSNone:
+	case SMTPTLSImplicit:
+		config.Port = "465"
+	default:
+		return MailConfig{}, fmt.Errorf("invalid SMTP_TLS: %q", config.TLSMode)
+	}
+
+	if authMode := os.Getenv("SMTP_AUTH"); authMode != "" {
+		config.AuthMode = authMode
+	}
+	switch config.AuthMode {
+	case SMTPAuthPlain, SMTPAuthLogin, SMTPAuthCRAMMD5, SMTPAuthNone:
+	default:
+		return MailConfig{}, fmt.Errorf("invalid SMTP_AUTH: %q", config.AuthMode)
+	}
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		config.Host = host
+	}
+
+	if port := os.Getenv("SMTP_PORT"); port != "" {
+		if value, err := strconv.Atoi(port); err != nil || value <= 0 || value > 65535 {
+			return MailConfig{}, fmt.Errorf("invalid SMTP_PORT: %q", port)
+		}
+		config.Port = port
+	}
+
+	config.Username = os.Getenv("SMTP_USERNAME")
+	config.Password = os.Getenv("SMTP_PASSWORD")
+
+	config.From = os.Getenv("SMTP_FROM")
+	if config.From == "" {
+		config.From = config.Username
+	}
+	if config.Transport == MailTransportSMTP && config.From == "" {
+		return MailConfig{}, errors.New("SMTP_FROM or SMTP_USERNAME must be set for smtp transport")
+	}
+
+	return config, nil
+}
@@ -1,16 +1,89 @@
 package notifier
 
-import "birthdayReminder/internal/repository/user"
+import (
+	"birthdayReminder/internal/repository/announcement"
+	"birthdayReminder/internal/repository/inbox"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/push"
+	"birthdayReminder/internal/repository/subscription"
+	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/repository/webhook"
+	"birthdayReminder/internal/stream"
+	"birthdayReminder/internal/webpush"
+	"time"
+)
 
+//go:generate mockgen -source=contract.go -destination=mocks/mockRepo.go
 type UserRepository interface {
 	CreateUser(user *user.User, hashedPassword []byte) error
 	GetUserByEmail(email string) (*user.User, error)
+	GetUserByID(id int) (*user.User, error)
 	GetAvailableUsersForSubscription(userID int) ([]user.User, error)
-	GetUsersWithBirthdayTomorrow() ([]user.User, error)
+	GetUsersWithBirthdayBetween(from, to time.Time) ([]user.User, error)
+	GetSummaryRecipients() ([]user.User, error)
 	GetSubscribers(userID int) ([]user.User, error)
 }
 
 type SubscriptionRepository interface {
-	CreateSubscription(userID int, relatedUserID int) error
+	CreateSubscription(userID int, relatedUserID int, offsets []int) error
 	UnsubscribeUser(userID int, relatedUserID int) error
+	GetAllDetails() ([]subscription.Details, error)
+	GetByUsers(userID int, relatedUserID int) (*subscription.Subscription, error)
+}
+
+// Locker - распределённая блокировка, чтобы цикл выполнял только один экземпляр сервиса.
+type Locker interface {
+	TryLock(key int64) (unlock func(), acquired bool, err error)
+}
+
+// TelegramClient отправляет сообщения через Telegram Bot API.
+type TelegramClient interface {
+	SendMessage(chatID int64, text string) error
+}
+
+type NotificationRepository interface {
+	Claim(n *notification.Notification) (bool, error)
+	MarkSent(id int) error
+	MarkFailed(id int, sendErr error, nextAttemptAt time.Time) error
+	MarkDead(id int, sendErr error) error
+	MarkSkipped(id int) error
+	Postpone(id int, until time.Time) error
+	ClaimDue(limit int) ([]notification.Notification, error)
+}
+
+type WebhookRepository interface {
+	HasTargets(userID int) (bool, error)
+	GetTargets(userID int, eventID string) ([]webhook.Webhook, error)
+	RecordDelivery(d *webhook.Delivery) error
+}
+
+type AnnouncementRepository interface {
+	GetAll() ([]announcement.Channel, error)
+	Claim(channelID, celebrantID int, day time.Time) (bool, error)
+	Release(channelID, celebrantID int, day time.Time) error
+}
+
+// ChatPoster публикует сообщение в командный чат по адресу incoming webhook.
+type ChatPoster interface {
+	Post(url, text string) error
+}
+
+type InboxRepository interface {
+	Add(item *inbox.Item) (bool, error)
+}
+
+// EventPublisher передаёт события подключённым клиентам в реальном времени.
+type EventPublisher interface {
+	Publish(e stream.Event) error
+}
+
+type PushRepository interface {
+	GetPending(userID int, eventID string) ([]push.Subscription, error)
+	RecordDelivery(subscriptionID int, eventID string) error
+	HasSubscriptions(userID int) (bool, error)
+	DeleteByEndpoint(endpoint string) error
+}
+
+type PushClient interface {
+	Send(sub webpush.Subscription, payload []byte, opts webpush.Options) error
 }
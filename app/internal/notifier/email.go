@@ -0,0 +1,136 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/repository/user"
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"time"
+)
+
+const ChannelEmail = "email"
+
+// MailTransport передает готовое MIME-письмо: по SMTP или в файлы для локальной разработки.
+type MailTransport interface {
+	Deliver(from string, to []string, message []byte) error
+}
+
+// EmailSender - канал email поверх выбранного MailTransport.
+type EmailSender struct {
+	from      string
+	transport MailTransport
+}
+
+func NewEmailSender(from string, transport MailTransport) *EmailSender {
+	return &EmailSender{
+		from:      from,
+		transport: transport,
+	}
+}
+
+// NewEmailSenderFromConfig собирает EmailSender с транспортом, указанным в config.Transport.
+func NewEmailSenderFromConfig(config MailConfig) (*EmailSender, error) {
+	var transport MailTransport
+	switch config.Transport {
+	case MailTransportSMTP:
+		transport = NewSMTPTransport(config)
+	case MailTransportFile:
+		transport = NewFileTransport(config.Dir)
+	case MailTransportMaildir:
+		transport = NewMaildirTransport(config.Dir)
+	default:
+		return nil, fmt.Errorf("unknown mail transport %q", config.Transport)
+	}
+	return NewEmailSender(config.From, transport), nil
+}
+
+func (s *EmailSender) Channel() string {
+	return ChannelEmail
+}
+
+// Accepts не пропускает неподтвержденные адреса: это может быть опечатка или чужой ящик.
+func (s *EmailSender) Accepts(recipient user.User) bool {
+	return recipient.Email != "" && recipient.EmailVerified
+}
+
+func (s *EmailSender) Send(recipient user.User, msg Message) error {
+	body, err := buildEmail(s.from, recipient.Email, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := s.transport.Deliver(s.from, []string{recipient.Email}, body); err != nil {
+		return fmt.Errorf("error sending email: %v", err)
+	}
+	return nil
+}
+
+// SendVerification отправляет письмо со ссылкой подтверждения адреса. Accepts здесь не проверяется:
+// письмо нужно как раз для неподтвержденного адреса.
+func (s *EmailSender) SendVerification(recipient user.User, link string) error {
+	msg, err := defaultTemplates.Render(KindVerification, recipient.Locale, TemplateData{SubscriberName: recipient.Name, Link: link})
+	if err != nil {
+		return err
+	}
+	msg.Kind = KindVerification
+	return s.Send(recipient, msg)
+}
+
+// buildEmail собирает MIME-письмо: text/plain или multipart/alternative, если есть HTML-версия.
+func buildEmail(from, to string, msg Message, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.HTMLBody == "" {
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, msg.Body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/plain; charset=utf-8", content: msg.Body},
+		{contentType: "text/html; charset=utf-8", content: msg.HTMLBody},
+	}
+	for _, part := range parts {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w interface{ Write([]byte) (int, error) }, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
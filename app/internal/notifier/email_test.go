@@ -0,0 +1,61 @@
+package notifier
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildEmail(t *testing.T) {
+	date := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Plain text", func(t *testing.T) {
+		raw, err := buildEmail("from@example.com", "to@example.com", Message{Subject: "Завтра день рождения", Body: "Привет!"}, date)
+		require.NoError(t, err)
+
+		msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+		require.NoError(t, err)
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		require.NoError(t, err)
+		assert.Equal(t, "Завтра день рождения", subject)
+		assert.Equal(t, "text/plain; charset=utf-8", msg.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+		require.NoError(t, err)
+		assert.Equal(t, "Привет!", string(body))
+	})
+
+	t.Run("Multipart with HTML", func(t *testing.T) {
+		raw, err := buildEmail("from@example.com", "to@example.com", Message{Subject: "Hi", Body: "text", HTMLBody: "<p>html</p>"}, date)
+		require.NoError(t, err)
+
+		msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+		require.NoError(t, err)
+
+		mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		assert.Equal(t, "multipart/alternative", mediaType)
+
+		reader := multipart.NewReader(msg.Body, params["boundary"])
+		var contents []string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			body, err := io.ReadAll(part)
+			require.NoError(t, err)
+			contents = append(contents, part.Header.Get("Content-Type")+": "+string(body))
+		}
+		assert.Equal(t, []string{"text/plain; charset=utf-8: text", "text/html; charset=utf-8: <p>html</p>"}, contents)
+	})
+}
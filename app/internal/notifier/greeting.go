@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/birthday"
+	"birthdayReminder/internal/repository/user"
+	"fmt"
+	"log"
+	"time"
+)
+
+// SendGreetings поздравляет именинников во встроенном центре уведомлений: в день рождения
+// пользователь получает поздравление в свой час отправки по местному времени, а подключённые
+// клиенты - событие greeting в потоке. Другие каналы поздравления не отправляют.
+// Повторный запуск поздравление не дублирует: центр уведомлений не принимает событие с тем же ID.
+func (n *Notifier) SendGreetings() {
+	n.sendGreetings(time.Now())
+}
+
+func (n *Notifier) sendGreetings(now time.Time) {
+	sender, ok := n.senders.Get(ChannelInApp)
+	if !ok {
+		return
+	}
+
+	// местная дата пользователя отличается от даты по UTC не больше чем на день
+	utc := now.UTC()
+	celebrants, err := n.userRepo.GetUsersWithBirthdayBetween(utc.AddDate(0, 0, -1), utc.AddDate(0, 0, 1))
+	if err != nil {
+		log.Println("Error fetching celebrants for greetings:", err)
+		return
+	}
+
+	for _, celebrant := range celebrants {
+		if !celebrant.ChannelEnabled(ChannelInApp) {
+			continue
+		}
+		day, ok := greetingDay(celebrant, now)
+		if !ok {
+			continue
+		}
+
+		msg, err := n.templates.Render(KindGreeting, celebrant.Locale, TemplateData{
+			SubscriberName: celebrant.Name,
+			CelebrantName:  celebrant.Name,
+			Age:            birthday.Age(celebrant.DateOfBirth, day),
+			Date:           day,
+		})
+		if err != nil {
+			log.Printf("Error rendering greeting for user ID %d: %v\n", celebrant.ID, err)
+			continue
+		}
+		msg.Kind = KindGreeting
+		msg.Celebrant = celebrant
+		// поздравление приходит не чаще раза в год
+		msg.EventID = eventID(celebrant.ID, fmt.Sprintf("greeting:%d", day.Year()))
+
+		// неудачная попытка повторится следующим циклом
+		if err := sender.Send(celebrant, msg); err != nil {
+			log.Printf("Error sending greeting to user ID %d: %v\n", celebrant.ID, err)
+		}
+	}
+}
+
+// greetingDay возвращает местную дату, если у пользователя сегодня день рождения и наступил его час отправки.
+func greetingDay(u user.User, now time.Time) (time.Time, bool) {
+	local := now.In(loadLocation(u.TimeZone))
+	if local.Hour() < u.NotifyHour || u.InQuietHours(local.Hour()) {
+		return time.Time{}, false
+	}
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	if !birthday.IsOn(u.DateOfBirth, today, birthday.Policy(u.LeapPolicy)) {
+		return time.Time{}, false
+	}
+	return today, true
+}
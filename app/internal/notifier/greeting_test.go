@@ -0,0 +1,102 @@
+package notifier
+
+import (
+	mock_notifier "birthdayReminder/internal/notifier/mocks"
+	"birthdayReminder/internal/repository/inbox"
+	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/stream"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGreetingDay(t *testing.T) {
+	// 17 октября 22:30 UTC - во Владивостоке уже 18 октября
+	now := time.Date(2026, time.October, 17, 22, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		user     user.User
+		expected bool
+	}{
+		{
+			name:     "Birthday today after notify hour",
+			user:     user.User{TimeZone: "UTC", NotifyHour: 12, DateOfBirth: time.Date(1990, time.October, 17, 0, 0, 0, 0, time.UTC)},
+			expected: true,
+		},
+		{
+			name:     "Birthday today in the user's time zone",
+			user:     user.User{TimeZone: "Asia/Vladivostok", NotifyHour: 8, DateOfBirth: time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC)},
+			expected: true,
+		},
+		{
+			name:     "Before notify hour",
+			user:     user.User{TimeZone: "UTC", NotifyHour: 23, DateOfBirth: time.Date(1990, time.October, 17, 0, 0, 0, 0, time.UTC)},
+			expected: false,
+		},
+		{
+			name:     "Quiet hours",
+			user:     user.User{TimeZone: "UTC", NotifyHour: 9, QuietStart: 22, QuietEnd: 8, DateOfBirth: time.Date(1990, time.October, 17, 0, 0, 0, 0, time.UTC)},
+			expected: false,
+		},
+		{
+			name:     "Birthday tomorrow",
+			user:     user.User{TimeZone: "UTC", NotifyHour: 12, DateOfBirth: time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC)},
+			expected: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := greetingDay(tt.user, now)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
+func TestSendGreetings(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	anna := user.User{ID: 1, Name: "Anna", Email: "anna@example.com", TimeZone: "UTC", NotifyHour: 9, DateOfBirth: time.Date(1996, time.October, 17, 0, 0, 0, 0, time.UTC)}
+	oleg := user.User{ID: 3, Name: "Oleg", Email: "oleg@example.com", TimeZone: "UTC", NotifyHour: 9, DateOfBirth: time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC)}
+	// отключил центр уведомлений
+	ivan := user.User{ID: 4, Name: "Ivan", TimeZone: "UTC", NotifyHour: 9, DateOfBirth: time.Date(1990, time.October, 17, 0, 0, 0, 0, time.UTC), DisabledChannels: []string{ChannelInApp}}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_notifier.NewMockUserRepository(ctrl)
+	mockInboxRepo := mock_notifier.NewMockInboxRepository(ctrl)
+	mockEvents := mock_notifier.NewMockEventPublisher(ctrl)
+	mockUserRepo.EXPECT().GetUsersWithBirthdayBetween(now.AddDate(0, 0, -1), now.AddDate(0, 0, 1)).Return([]user.User{anna, oleg, ivan}, nil)
+	mockInboxRepo.EXPECT().Add(gomock.Any()).DoAndReturn(func(item *inbox.Item) (bool, error) {
+		assert.Equal(t, anna.ID, item.UserID)
+		assert.Equal(t, KindGreeting, item.Kind)
+		assert.Equal(t, "С днем рождения, Anna!", item.Title)
+		assert.Contains(t, item.Body, "исполняется 30")
+		assert.Equal(t, "1:greeting:2026", item.EventID)
+		item.ID = 10
+		return true, nil
+	})
+	mockEvents.EXPECT().Publish(gomock.Any()).DoAndReturn(func(e stream.Event) error {
+		assert.Equal(t, stream.EventGreeting, e.Type)
+		assert.Equal(t, anna.ID, e.UserID)
+		return nil
+	})
+
+	// остальные каналы поздравления не получают
+	email := &memorySender{channel: "email"}
+	n := New(mockUserRepo, nil, nil, nil, NewRegistry(email, NewInAppSender(mockInboxRepo, mockEvents)), nil, DefaultConfig())
+	n.sendGreetings(now)
+
+	assert.Empty(t, email.sent)
+}
+
+func TestSendGreetingsWithoutInApp(t *testing.T) {
+	email := &memorySender{channel: "email"}
+	// без центра уведомлений пользователи даже не запрашиваются
+	n := New(nil, nil, nil, nil, NewRegistry(email), nil, DefaultConfig())
+	n.sendGreetings(time.Now())
+
+	assert.Empty(t, email.sent)
+}
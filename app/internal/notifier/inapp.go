@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/repository/inbox"
+	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/stream"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+const ChannelInApp = "inapp"
+
+// InAppSender сохраняет уведомления во встроенный центр уведомлений, который показывают веб- и мобильный клиенты,
+// и сообщает о новых уведомлениях подключённым к потоку клиентам.
+type InAppSender struct {
+	repo   InboxRepository
+	events EventPublisher
+}
+
+// inboxEvent - данные события потока о новом уведомлении; текст клиент получает через /api/notifications.
+type inboxEvent struct {
+	ID          int       `json:"id"`
+	Kind        string    `json:"kind"`
+	Title       string    `json:"title"`
+	CelebrantID int       `json:"celebrant_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewInAppSender создаёт канал; events может быть nil, тогда события в поток не отправляются.
+func NewInAppSender(repo InboxRepository, events EventPublisher) *InAppSender {
+	return &InAppSender{repo: repo, events: events}
+}
+
+func (s *InAppSender) Channel() string {
+	return ChannelInApp
+}
+
+func (s *InAppSender) Accepts(recipient user.User) bool {
+	return true
+}
+
+func (s *InAppSender) Send(recipient user.User, msg Message) error {
+	item := inbox.Item{
+		UserID:      recipient.ID,
+		Kind:        msg.Kind,
+		Title:       msg.Subject,
+		Body:        msg.Body,
+		CelebrantID: msg.Celebrant.ID,
+		EventID:     msg.EventID,
+	}
+	added, err := s.repo.Add(&item)
+	if err != nil || !added || s.events == nil {
+		return err
+	}
+
+	// поток - лишь ускорение доставки: уведомление уже сохранено, поэтому ошибку только логируем
+	eventType := stream.EventNotification
+	if msg.Kind == KindGreeting {
+		eventType = stream.EventGreeting
+	}
+	data, err := json.Marshal(inboxEvent{ID: item.ID, Kind: item.Kind, Title: item.Title, CelebrantID: item.CelebrantID, CreatedAt: item.CreatedAt})
+	if err == nil {
+		err = s.events.Publish(stream.Event{Type: eventType, UserID: recipient.ID, Data: data})
+	}
+	if err != nil {
+		log.Printf("Error publishing notification event for user ID %d: %v\n", recipient.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	mock_notifier "birthdayReminder/internal/notifier/mocks"
+	"birthdayReminder/internal/repository/inbox"
+	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/stream"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInAppSender(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_notifier.NewMockInboxRepository(ctrl)
+	repo.EXPECT().Add(&inbox.Item{
+		UserID:      2,
+		Kind:        KindReminder,
+		Title:       "Напоминание",
+		Body:        "Завтра день рождения у Anna!",
+		CelebrantID: 1,
+		EventID:     "2:reminder:1:2026:1",
+	}).DoAndReturn(func(item *inbox.Item) (bool, error) {
+		item.ID = 9
+		return true, nil
+	})
+
+	events := mock_notifier.NewMockEventPublisher(ctrl)
+	events.EXPECT().Publish(gomock.Any()).DoAndReturn(func(e stream.Event) error {
+		assert.Equal(t, stream.EventNotification, e.Type)
+		assert.Equal(t, 2, e.UserID)
+		assert.Contains(t, string(e.Data), `"id":9,"kind":"reminder","title":"Напоминание","celebrant_id":1`)
+		return nil
+	})
+
+	sender := NewInAppSender(repo, events)
+	recipient := user.User{ID: 2}
+	assert.True(t, sender.Accepts(recipient))
+	assert.NoError(t, sender.Send(recipient, Message{
+		Subject:   "Напоминание",
+		Body:      "Завтра день рождения у Anna!",
+		Celebrant: user.User{ID: 1, Name: "Anna"},
+		Kind:      KindReminder,
+		EventID:   "2:reminder:1:2026:1",
+	}))
+}
+
+func TestInAppSenderPublishesGreetings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_notifier.NewMockInboxRepository(ctrl)
+	repo.EXPECT().Add(gomock.Any()).DoAndReturn(func(item *inbox.Item) (bool, error) {
+		item.ID = 11
+		return true, nil
+	})
+
+	events := mock_notifier.NewMockEventPublisher(ctrl)
+	events.EXPECT().Publish(gomock.Any()).DoAndReturn(func(e stream.Event) error {
+		assert.Equal(t, stream.EventGreeting, e.Type)
+		assert.Equal(t, 1, e.UserID)
+		assert.Contains(t, string(e.Data), `"id":11,"kind":"greeting"`)
+		return nil
+	})
+
+	sender := NewInAppSender(repo, events)
+	assert.NoError(t, sender.Send(user.User{ID: 1}, Message{Subject: "С днем рождения, Anna!", Kind: KindGreeting, EventID: "1:greeting:2026"}))
+}
@@ -0,0 +1,66 @@
+package notifier
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+var mailFileCounter uint64
+
+// uniqueMailName строит имя файла в духе Maildir: время, pid, счетчик и имя хоста.
+func uniqueMailName() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "localhost"
+	}
+	now := time.Now()
+	return fmt.Sprintf("%d.M%dP%dQ%d.%s", now.Unix(), now.Nanosecond()/1000, os.Getpid(), atomic.AddUint64(&mailFileCounter, 1), hostname)
+}
+
+// FileTransport складывает письма в каталог в виде .eml файлов.
+type FileTransport struct {
+	dir string
+}
+
+func NewFileTransport(dir string) *FileTransport {
+	return &FileTransport{dir: dir}
+}
+
+func (t *FileTransport) Deliver(from string, to []string, message []byte) error {
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(t.dir, uniqueMailName()+".eml"), message, 0o644)
+}
+
+// MaildirTransport складывает письма в Maildir (tmp/new/cur), который читают почтовые клиенты.
+type MaildirTransport struct {
+	dir string
+}
+
+func NewMaildirTransport(dir string) *MaildirTransport {
+	return &MaildirTransport{dir: dir}
+}
+
+func (t *MaildirTransport) Deliver(from string, to []string, message []byte) error {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(t.dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+
+	// письмо появляется в new только целиком: сначала пишем в tmp, затем переименовываем
+	name := uniqueMailName()
+	tmpPath := filepath.Join(t.dir, "tmp", name)
+	if err := os.WriteFile(tmpPath, message, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, filepath.Join(t.dir, "new", name)); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
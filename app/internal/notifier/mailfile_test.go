@@ -0,0 +1,94 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/repository/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileTransport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	transport := NewFileTransport(dir)
+
+	require.NoError(t, transport.Deliver("from@example.com", []string{"to@example.com"}, []byte("first")))
+	require.NoError(t, transport.Deliver("from@example.com", []string{"to@example.com"}, []byte("second")))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	var contents []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		contents = append(contents, string(data))
+	}
+	assert.ElementsMatch(t, []string{"first", "second"}, contents)
+}
+
+func TestMaildirTransport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, NewMaildirTransport(dir).Deliver("from@example.com", []string{"to@example.com"}, []byte("hello")))
+
+	newFiles, err := os.ReadDir(filepath.Join(dir, "new"))
+	require.NoError(t, err)
+	require.Len(t, newFiles, 1)
+	assert.False(t, strings.HasSuffix(newFiles[0].Name(), ".eml"))
+
+	data, err := os.ReadFile(filepath.Join(dir, "new", newFiles[0].Name()))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	tmpFiles, err := os.ReadDir(filepath.Join(dir, "tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, tmpFiles)
+}
+
+func TestEmailSenderWritesFiles(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := NewEmailSenderFromConfig(MailConfig{Transport: MailTransportFile, From: "robot@example.com", Dir: dir})
+	require.NoError(t, err)
+
+	err = sender.Send(user.User{Email: "ivan@example.com"}, Message{Subject: "Hi", Body: "Hello"})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "From: robot@example.com\r\n")
+	assert.Contains(t, string(data), "To: ivan@example.com\r\n")
+
+	_, err = NewEmailSenderFromConfig(MailConfig{Transport: "pigeon"})
+	assert.Error(t, err)
+}
+
+func TestEmailSenderVerification(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := NewEmailSenderFromConfig(MailConfig{Transport: MailTransportFile, From: "robot@example.com", Dir: dir})
+	require.NoError(t, err)
+
+	recipient := user.User{Name: "Ivan", Email: "ivan@example.com", Locale: "en"}
+	assert.False(t, sender.Accepts(recipient))
+	recipient.EmailVerified = true
+	assert.True(t, sender.Accepts(recipient))
+
+	// письмо подтверждения уходит и на неподтвержденный адрес
+	recipient.EmailVerified = false
+	require.NoError(t, sender.SendVerification(recipient, "http://localhost:8080/api/verify-email?token=abc"))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Subject: Confirm your email address\r\n")
+	assert.Contains(t, string(data), "token=3Dabc")
+}
@@ -0,0 +1,792 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_notifier is a generated GoMock package.
+package mock_notifier
+
+import (
+	announcement "birthdayReminder/internal/repository/announcement"
+	inbox "birthdayReminder/internal/repository/inbox"
+	notification "birthdayReminder/internal/repository/notification"
+	push "birthdayReminder/internal/repository/push"
+	subscription "birthdayReminder/internal/repository/subscription"
+	user "birthdayReminder/internal/repository/user"
+	webhook "birthdayReminder/internal/repository/webhook"
+	stream "birthdayReminder/internal/stream"
+	webpush "birthdayReminder/internal/webpush"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockUserRepository) CreateUser(user *user.User, hashedPassword []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", user, hashedPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserRepositoryMockRecorder) CreateUser(user, hashedPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepository)(nil).CreateUser), user, hashedPassword)
+}
+
+// GetAvailableUsersForSubscription mocks base method.
+func (m *MockUserRepository) GetAvailableUsersForSubscription(userID int) ([]user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableUsersForSubscription", userID)
+	ret0, _ := ret[0].([]user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableUsersForSubscription indicates an expected call of GetAvailableUsersForSubscription.
+func (mr *MockUserRepositoryMockRecorder) GetAvailableUsersForSubscription(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableUsersForSubscription", reflect.TypeOf((*MockUserRepository)(nil).GetAvailableUsersForSubscription), userID)
+}
+
+// GetSubscribers mocks base method.
+func (m *MockUserRepository) GetSubscribers(userID int) ([]user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscribers", userID)
+	ret0, _ := ret[0].([]user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscribers indicates an expected call of GetSubscribers.
+func (mr *MockUserRepositoryMockRecorder) GetSubscribers(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribers", reflect.TypeOf((*MockUserRepository)(nil).GetSubscribers), userID)
+}
+
+// GetSummaryRecipients mocks base method.
+func (m *MockUserRepository) GetSummaryRecipients() ([]user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSummaryRecipients")
+	ret0, _ := ret[0].([]user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSummaryRecipients indicates an expected call of GetSummaryRecipients.
+func (mr *MockUserRepositoryMockRecorder) GetSummaryRecipients() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummaryRecipients", reflect.TypeOf((*MockUserRepository)(nil).GetSummaryRecipients))
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockUserRepository) GetUserByEmail(email string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", email)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockUserRepositoryMockRecorder) GetUserByEmail(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepository)(nil).GetUserByEmail), email)
+}
+
+// GetUserByID mocks base method.
+func (m *MockUserRepository) GetUserByID(id int) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", id)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserRepositoryMockRecorder) GetUserByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepository)(nil).GetUserByID), id)
+}
+
+// GetUsersWithBirthdayBetween mocks base method.
+func (m *MockUserRepository) GetUsersWithBirthdayBetween(from, to time.Time) ([]user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersWithBirthdayBetween", from, to)
+	ret0, _ := ret[0].([]user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersWithBirthdayBetween indicates an expected call of GetUsersWithBirthdayBetween.
+func (mr *MockUserRepositoryMockRecorder) GetUsersWithBirthdayBetween(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersWithBirthdayBetween", reflect.TypeOf((*MockUserRepository)(nil).GetUsersWithBirthdayBetween), from, to)
+}
+
+// MockSubscriptionRepository is a mock of SubscriptionRepository interface.
+type MockSubscriptionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionRepositoryMockRecorder
+}
+
+// MockSubscriptionRepositoryMockRecorder is the mock recorder for MockSubscriptionRepository.
+type MockSubscriptionRepositoryMockRecorder struct {
+	mock *MockSubscriptionRepository
+}
+
+// NewMockSubscriptionRepository creates a new mock instance.
+func NewMockSubscriptionRepository(ctrl *gomock.Controller) *MockSubscriptionRepository {
+	mock := &MockSubscriptionRepository{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionRepository) EXPECT() *MockSubscriptionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSubscription mocks base method.
+func (m *MockSubscriptionRepository) CreateSubscription(userID, relatedUserID int, offsets []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", userID, relatedUserID, offsets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockSubscriptionRepositoryMockRecorder) CreateSubscription(userID, relatedUserID, offsets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockSubscriptionRepository)(nil).CreateSubscription), userID, relatedUserID, offsets)
+}
+
+// GetAllDetails mocks base method.
+func (m *MockSubscriptionRepository) GetAllDetails() ([]subscription.Details, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllDetails")
+	ret0, _ := ret[0].([]subscription.Details)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllDetails indicates an expected call of GetAllDetails.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetAllDetails() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllDetails", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetAllDetails))
+}
+
+// GetByUsers mocks base method.
+func (m *MockSubscriptionRepository) GetByUsers(userID, relatedUserID int) (*subscription.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUsers", userID, relatedUserID)
+	ret0, _ := ret[0].(*subscription.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUsers indicates an expected call of GetByUsers.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetByUsers(userID, relatedUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsers", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetByUsers), userID, relatedUserID)
+}
+
+// UnsubscribeUser mocks base method.
+func (m *MockSubscriptionRepository) UnsubscribeUser(userID, relatedUserID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsubscribeUser", userID, relatedUserID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsubscribeUser indicates an expected call of UnsubscribeUser.
+func (mr *MockSubscriptionRepositoryMockRecorder) UnsubscribeUser(userID, relatedUserID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeUser", reflect.TypeOf((*MockSubscriptionRepository)(nil).UnsubscribeUser), userID, relatedUserID)
+}
+
+// MockLocker is a mock of Locker interface.
+type MockLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockerMockRecorder
+}
+
+// MockLockerMockRecorder is the mock recorder for MockLocker.
+type MockLockerMockRecorder struct {
+	mock *MockLocker
+}
+
+// NewMockLocker creates a new mock instance.
+func NewMockLocker(ctrl *gomock.Controller) *MockLocker {
+	mock := &MockLocker{ctrl: ctrl}
+	mock.recorder = &MockLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocker) EXPECT() *MockLockerMockRecorder {
+	return m.recorder
+}
+
+// TryLock mocks base method.
+func (m *MockLocker) TryLock(key int64) (func(), bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLock", key)
+	ret0, _ := ret[0].(func())
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TryLock indicates an expected call of TryLock.
+func (mr *MockLockerMockRecorder) TryLock(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLock", reflect.TypeOf((*MockLocker)(nil).TryLock), key)
+}
+
+// MockTelegramClient is a mock of TelegramClient interface.
+type MockTelegramClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTelegramClientMockRecorder
+}
+
+// MockTelegramClientMockRecorder is the mock recorder for MockTelegramClient.
+type MockTelegramClientMockRecorder struct {
+	mock *MockTelegramClient
+}
+
+// NewMockTelegramClient creates a new mock instance.
+func NewMockTelegramClient(ctrl *gomock.Controller) *MockTelegramClient {
+	mock := &MockTelegramClient{ctrl: ctrl}
+	mock.recorder = &MockTelegramClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTelegramClient) EXPECT() *MockTelegramClientMockRecorder {
+	return m.recorder
+}
+
+// SendMessage mocks base method.
+func (m *MockTelegramClient) SendMessage(chatID int64, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", chatID, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockTelegramClientMockRecorder) SendMessage(chatID, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockTelegramClient)(nil).SendMessage), chatID, text)
+}
+
+// MockNotificationRepository is a mock of NotificationRepository interface.
+type MockNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepositoryMockRecorder
+}
+
+// MockNotificationRepositoryMockRecorder is the mock recorder for MockNotificationRepository.
+type MockNotificationRepositoryMockRecorder struct {
+	mock *MockNotificationRepository
+}
+
+// NewMockNotificationRepository creates a new mock instance.
+func NewMockNotificationRepository(ctrl *gomock.Controller) *MockNotificationRepository {
+	mock := &MockNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepository) EXPECT() *MockNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockNotificationRepository) Claim(n *notification.Notification) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", n)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockNotificationRepositoryMockRecorder) Claim(n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockNotificationRepository)(nil).Claim), n)
+}
+
+// ClaimDue mocks base method.
+func (m *MockNotificationRepository) ClaimDue(limit int) ([]notification.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", limit)
+	ret0, _ := ret[0].([]notification.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockNotificationRepositoryMockRecorder) ClaimDue(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockNotificationRepository)(nil).ClaimDue), limit)
+}
+
+// MarkDead mocks base method.
+func (m *MockNotificationRepository) MarkDead(id int, sendErr error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDead", id, sendErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDead indicates an expected call of MarkDead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkDead(id, sendErr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkDead), id, sendErr)
+}
+
+// MarkFailed mocks base method.
+func (m *MockNotificationRepository) MarkFailed(id int, sendErr error, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", id, sendErr, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockNotificationRepositoryMockRecorder) MarkFailed(id, sendErr, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockNotificationRepository)(nil).MarkFailed), id, sendErr, nextAttemptAt)
+}
+
+// MarkSent mocks base method.
+func (m *MockNotificationRepository) MarkSent(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSent", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSent indicates an expected call of MarkSent.
+func (mr *MockNotificationRepositoryMockRecorder) MarkSent(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSent", reflect.TypeOf((*MockNotificationRepository)(nil).MarkSent), id)
+}
+
+// MarkSkipped mocks base method.
+func (m *MockNotificationRepository) MarkSkipped(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSkipped", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSkipped indicates an expected call of MarkSkipped.
+func (mr *MockNotificationRepositoryMockRecorder) MarkSkipped(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSkipped", reflect.TypeOf((*MockNotificationRepository)(nil).MarkSkipped), id)
+}
+
+// Postpone mocks base method.
+func (m *MockNotificationRepository) Postpone(id int, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Postpone", id, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Postpone indicates an expected call of Postpone.
+func (mr *MockNotificationRepositoryMockRecorder) Postpone(id, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Postpone", reflect.TypeOf((*MockNotificationRepository)(nil).Postpone), id, until)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetTargets mocks base method.
+func (m *MockWebhookRepository) GetTargets(userID int, eventID string) ([]webhook.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargets", userID, eventID)
+	ret0, _ := ret[0].([]webhook.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargets indicates an expected call of GetTargets.
+func (mr *MockWebhookRepositoryMockRecorder) GetTargets(userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargets", reflect.TypeOf((*MockWebhookRepository)(nil).GetTargets), userID, eventID)
+}
+
+// HasTargets mocks base method.
+func (m *MockWebhookRepository) HasTargets(userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasTargets", userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasTargets indicates an expected call of HasTargets.
+func (mr *MockWebhookRepositoryMockRecorder) HasTargets(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasTargets", reflect.TypeOf((*MockWebhookRepository)(nil).HasTargets), userID)
+}
+
+// RecordDelivery mocks base method.
+func (m *MockWebhookRepository) RecordDelivery(d *webhook.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordDelivery", d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordDelivery indicates an expected call of RecordDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) RecordDelivery(d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).RecordDelivery), d)
+}
+
+// MockAnnouncementRepository is a mock of AnnouncementRepository interface.
+type MockAnnouncementRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnnouncementRepositoryMockRecorder
+}
+
+// MockAnnouncementRepositoryMockRecorder is the mock recorder for MockAnnouncementRepository.
+type MockAnnouncementRepositoryMockRecorder struct {
+	mock *MockAnnouncementRepository
+}
+
+// NewMockAnnouncementRepository creates a new mock instance.
+func NewMockAnnouncementRepository(ctrl *gomock.Controller) *MockAnnouncementRepository {
+	mock := &MockAnnouncementRepository{ctrl: ctrl}
+	mock.recorder = &MockAnnouncementRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnnouncementRepository) EXPECT() *MockAnnouncementRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockAnnouncementRepository) Claim(channelID, celebrantID int, day time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", channelID, celebrantID, day)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockAnnouncementRepositoryMockRecorder) Claim(channelID, celebrantID, day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockAnnouncementRepository)(nil).Claim), channelID, celebrantID, day)
+}
+
+// GetAll mocks base method.
+func (m *MockAnnouncementRepository) GetAll() ([]announcement.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]announcement.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAnnouncementRepositoryMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAnnouncementRepository)(nil).GetAll))
+}
+
+// Release mocks base method.
+func (m *MockAnnouncementRepository) Release(channelID, celebrantID int, day time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", channelID, celebrantID, day)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockAnnouncementRepositoryMockRecorder) Release(channelID, celebrantID, day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockAnnouncementRepository)(nil).Release), channelID, celebrantID, day)
+}
+
+// MockChatPoster is a mock of ChatPoster interface.
+type MockChatPoster struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatPosterMockRecorder
+}
+
+// MockChatPosterMockRecorder is the mock recorder for MockChatPoster.
+type MockChatPosterMockRecorder struct {
+	mock *MockChatPoster
+}
+
+// NewMockChatPoster creates a new mock instance.
+func NewMockChatPoster(ctrl *gomock.Controller) *MockChatPoster {
+	mock := &MockChatPoster{ctrl: ctrl}
+	mock.recorder = &MockChatPosterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChatPoster) EXPECT() *MockChatPosterMockRecorder {
+	return m.recorder
+}
+
+// Post mocks base method.
+func (m *MockChatPoster) Post(url, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", url, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockChatPosterMockRecorder) Post(url, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockChatPoster)(nil).Post), url, text)
+}
+
+// MockInboxRepository is a mock of InboxRepository interface.
+type MockInboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInboxRepositoryMockRecorder
+}
+
+// MockInboxRepositoryMockRecorder is the mock recorder for MockInboxRepository.
+type MockInboxRepositoryMockRecorder struct {
+	mock *MockInboxRepository
+}
+
+// NewMockInboxRepository creates a new mock instance.
+func NewMockInboxRepository(ctrl *gomock.Controller) *MockInboxRepository {
+	mock := &MockInboxRepository{ctrl: ctrl}
+	mock.recorder = &MockInboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInboxRepository) EXPECT() *MockInboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockInboxRepository) Add(item *inbox.Item) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", item)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockInboxRepositoryMockRecorder) Add(item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockInboxRepository)(nil).Add), item)
+}
+
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventPublisherMockRecorder
+}
+
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
+}
+
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventPublisher) Publish(e stream.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventPublisherMockRecorder) Publish(e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventPublisher)(nil).Publish), e)
+}
+
+// MockPushRepository is a mock of PushRepository interface.
+type MockPushRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPushRepositoryMockRecorder
+}
+
+// MockPushRepositoryMockRecorder is the mock recorder for MockPushRepository.
+type MockPushRepositoryMockRecorder struct {
+	mock *MockPushRepository
+}
+
+// NewMockPushRepository creates a new mock instance.
+func NewMockPushRepository(ctrl *gomock.Controller) *MockPushRepository {
+	mock := &MockPushRepository{ctrl: ctrl}
+	mock.recorder = &MockPushRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPushRepository) EXPECT() *MockPushRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteByEndpoint mocks base method.
+func (m *MockPushRepository) DeleteByEndpoint(endpoint string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByEndpoint", endpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByEndpoint indicates an expected call of DeleteByEndpoint.
+func (mr *MockPushRepositoryMockRecorder) DeleteByEndpoint(endpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByEndpoint", reflect.TypeOf((*MockPushRepository)(nil).DeleteByEndpoint), endpoint)
+}
+
+// GetPending mocks base method.
+func (m *MockPushRepository) GetPending(userID int, eventID string) ([]push.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", userID, eventID)
+	ret0, _ := ret[0].([]push.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *MockPushRepositoryMockRecorder) GetPending(userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockPushRepository)(nil).GetPending), userID, eventID)
+}
+
+// HasSubscriptions mocks base method.
+func (m *MockPushRepository) HasSubscriptions(userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSubscriptions", userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasSubscriptions indicates an expected call of HasSubscriptions.
+func (mr *MockPushRepositoryMockRecorder) HasSubscriptions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSubscriptions", reflect.TypeOf((*MockPushRepository)(nil).HasSubscriptions), userID)
+}
+
+// RecordDelivery mocks base method.
+func (m *MockPushRepository) RecordDelivery(subscriptionID int, eventID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordDelivery", subscriptionID, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordDelivery indicates an expected call of RecordDelivery.
+func (mr *MockPushRepositoryMockRecorder) RecordDelivery(subscriptionID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordDelivery", reflect.TypeOf((*MockPushRepository)(nil).RecordDelivery), subscriptionID, eventID)
+}
+
+// MockPushClient is a mock of PushClient interface.
+type MockPushClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockPushClientMockRecorder
+}
+
+// MockPushClientMockRecorder is the mock recorder for MockPushClient.
+type MockPushClientMockRecorder struct {
+	mock *MockPushClient
+}
+
+// NewMockPushClient creates a new mock instance.
+func NewMockPushClient(ctrl *gomock.Controller) *MockPushClient {
+	mock := &MockPushClient{ctrl: ctrl}
+	mock.recorder = &MockPushClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPushClient) EXPECT() *MockPushClientMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockPushClient) Send(sub webpush.Subscription, payload []byte, opts webpush.Options) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", sub, payload, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockPushClientMockRecorder) Send(sub, payload, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockPushClient)(nil).Send), sub, payload, opts)
+}
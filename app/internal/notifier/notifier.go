@@ -1,101 +1,337 @@
 package notifier
 
 import (
+	"birthdayReminder/internal/birthday"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/subscription"
+	"birthdayReminder/internal/repository/user"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/go-co-op/gocron"
 	"log"
-	"net/smtp"
-	"os"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
+// digestEntryChannel - служебный канал отметок о напоминаниях, вошедших в дайджест.
+const digestEntryChannel = "digest"
+
 type Notifier struct {
 	userRepo         UserRepository
 	subscriptionRepo SubscriptionRepository
+	notificationRepo NotificationRepository
+	announcementRepo AnnouncementRepository
+	chatClient       ChatPoster
+	senders          *Registry
+	locker           Locker
+	retry            RetryPolicy
+	templates        *Templates
+	config           Config
+
+	scheduler *gocron.Scheduler
+	// cycleMu, summaryMu и retryMu не дают циклам рассылки, сводок и повторов пересекаться сами с собой
+	cycleMu   sync.Mutex
+	summaryMu sync.Mutex
+	retryMu   sync.Mutex
+	// running отслеживает выполняющиеся циклы для корректной остановки
+	running sync.WaitGroup
+	stateMu sync.Mutex
+	stopped bool
 }
 
-func New(userRepo UserRepository, subscriptionRepo SubscriptionRepository) Notifier {
-	return Notifier{
+func New(userRepo UserRepository, subscriptionRepo SubscriptionRepository, notificationRepo NotificationRepository, announcementRepo AnnouncementRepository, senders *Registry, locker Locker, config Config) *Notifier {
+	return &Notifier{
 		userRepo:         userRepo,
 		subscriptionRepo: subscriptionRepo,
+		notificationRepo: notificationRepo,
+		announcementRepo: announcementRepo,
+		chatClient:       NewChatWebhookClient(config.AllowPrivateTargets),
+		senders:          senders,
+		locker:           locker,
+		retry:            DefaultRetryPolicy,
+		templates:        defaultTemplates,
+		config:           config,
 	}
 }
 
-func (n *Notifier) StartBirthdayNotifier() {
-	log.Println("Initializing the scheduler")
-	s := gocron.NewScheduler(time.UTC)
-
-	loc, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		fmt.Println("Error loading location:", err)
-		return
-	}
-
-	s.ChangeLocation(loc)
-
-	_, err = s.Every(1).Day().At("12:15").Do(n.SendBirthdayNotifications)
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	s.StartAsync()
+// reminder - напоминание подписчику о дне рождения, наступающем через offset дней.
+type reminder struct {
+	subscriber user.User
+	celebrant  user.User
+	birthday   time.Time
+	offset     int
 }
 
+// SendBirthdayNotifications рассылает напоминания по всем подпискам, для которых
+// сегодня наступил один из сроков напоминания.
+// Функция идемпотентна: уже доставленные напоминания пропускаются,
+// поэтому её можно безопасно вызывать несколько раз в день и после рестарта.
 func (n *Notifier) SendBirthdayNotifications() {
-	users, err := n.userRepo.GetUsersWithBirthdayTomorrow()
+	subscriptions, err := n.subscriptionRepo.GetAllDetails()
 	if err != nil {
-		log.Println("Error fetching users with birthday tomorrow:", err)
+		log.Println("Error fetching subscriptions:", err)
 		return
 	}
 
-	if len(users) == 0 {
-		log.Println("No users found with birthday tomorrow.")
+	reminders := dueReminders(subscriptions, time.Now())
+	if len(reminders) == 0 {
+		log.Println("No reminders due today.")
 		return
 	}
 
-	for _, user := range users {
-		subscribers, err := n.userRepo.GetSubscribers(user.ID)
+	var digests [][]reminder
+	digestIndex := make(map[int]int)
+	for _, r := range reminders {
+		if r.subscriber.DeliveryMode == user.DeliveryDigest {
+			i, ok := digestIndex[r.subscriber.ID]
+			if !ok {
+				i = len(digests)
+				digestIndex[r.subscriber.ID] = i
+				digests = append(digests, nil)
+			}
+			digests[i] = append(digests[i], r)
+			continue
+		}
+
+		msg, err := n.reminderMessage(r)
 		if err != nil {
-			log.Println("Error fetching subscribers for user ID:", user.ID, "-", err)
+			log.Printf("Error rendering reminder for user ID %d: %v\n", r.subscriber.ID, err)
+			continue
+		}
+		n.deliver(r.subscriber, reminderRecord(r), msg)
+	}
+
+	for _, group := range digests {
+		subscriber := group[0].subscriber
+		// повторный запуск в тот же день отправляет дайджест только из напоминаний, не вошедших в прежние
+		entries, pending := n.claimDigestEntries(group)
+		if len(pending) == 0 {
 			continue
 		}
 
-		if len(subscribers) == 0 {
-			log.Printf("No subscribers found for user %s (ID: %d)\n", user.Name, user.ID)
+		msg, err := n.digestMessage(pending)
+		if err != nil {
+			log.Printf("Error rendering digest for user ID %d: %v\n", subscriber.ID, err)
 			continue
 		}
+		n.deliver(subscriber, digestRecord(pending), msg)
 
-		subject := "Happy Birthday Notification"
-		message := "Завтра день рождения у " + user.Name + "!" +
-			"Не забудьте поздравить!"
-		for _, subscriber := range subscribers {
-			if err := n.sendMessage(subscriber.Email, subject, message); err != nil {
-				log.Println("Error sending email to", subscriber.Email, ":", err)
-				continue
+		for _, entry := range entries {
+			if err := n.notificationRepo.MarkSent(entry.ID); err != nil {
+				log.Printf("Error recording digest entry ID %d: %v\n", entry.ID, err)
 			}
-			log.Printf("Sent birthday notification to %s for user %s (ID: %d)\n", subscriber.Email, user.Name, user.ID)
 		}
 	}
 }
 
-func (n *Notifier) sendMessage(email, subject, message string) error {
-	smtpHost := "smtp.mail.ru"
-	smtpPort := "587"
-	from := os.Getenv("SMTP_USERNAME")
-	Password := os.Getenv("SMTP_PASSWORD")
+// claimDigestEntries резервирует каждое напоминание группы за дайджестом и возвращает
+// записи-отметки вместе с напоминаниями, которые ещё не входили ни в один дайджест.
+func (n *Notifier) claimDigestEntries(group []reminder) ([]notification.Notification, []reminder) {
+	var entries []notification.Notification
+	var pending []reminder
+	for _, r := range group {
+		entry := digestEntry(r)
+		claimed, err := n.notificationRepo.Claim(&entry)
+		if err != nil {
+			log.Printf("Error claiming digest entry for user ID %d: %v\n", r.subscriber.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		entries = append(entries, entry)
+		pending = append(pending, r)
+	}
+	return entries, pending
+}
+
+// dueReminders отбирает напоминания, срок которых наступил к моменту now.
+// "Сегодня" и час отправки определяются в часовом поясе подписчика;
+// в период тишины напоминания откладываются до его окончания, а отложенные подписки пропускаются.
+func dueReminders(subscriptions []subscription.Details, now time.Time) []reminder {
+	locations := make(map[string]*time.Location)
 
-	//авторизация
-	auth := smtp.PlainAuth("", from, Password, smtpHost)
+	var reminders []reminder
+	for _, s := range subscriptions {
+		loc, ok := locations[s.Subscriber.TimeZone]
+		if !ok {
+			loc = loadLocation(s.Subscriber.TimeZone)
+			locations[s.Subscriber.TimeZone] = loc
+		}
 
-	msg := []byte(fmt.Sprintf("To: %s\r\n"+
-		"Subject: %s\r\n"+
-		"\r\n"+
-		"%s\r\n", email, subject, message))
+		local := now.In(loc)
+		if local.Hour() < s.Subscriber.NotifyHour || s.Subscriber.InQuietHours(local.Hour()) {
+			continue
+		}
+		today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		if s.SnoozedOn(today) {
+			continue
+		}
+		reminders = append(reminders, subscriptionReminders(s, today)...)
+	}
+	return reminders
+}
+
+// subscriptionReminders возвращает напоминания подписки, срок которых приходится на день today.
+func subscriptionReminders(s subscription.Details, today time.Time) []reminder {
+	var reminders []reminder
+	for _, offset := range s.Offsets {
+		day := today.AddDate(0, 0, offset)
+		if !birthday.IsOn(s.Celebrant.DateOfBirth, day, birthday.Policy(s.Celebrant.LeapPolicy)) {
+			continue
+		}
+		reminders = append(reminders, reminder{
+			subscriber: s.Subscriber,
+			celebrant:  s.Celebrant,
+			birthday:   day,
+			offset:     offset,
+		})
+	}
+	return reminders
+}
+
+// loadLocation возвращает часовой пояс пользователя, при ошибке - пояс по умолчанию.
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		name = user.DefaultTimeZone
+	}
 
-	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, from, []string{email}, msg)
+	loc, err := time.LoadLocation(name)
 	if err != nil {
-		return fmt.Errorf("error sending email: %v", err)
+		log.Printf("Error loading location %q: %v\n", name, err)
+		loc, err = time.LoadLocation(user.DefaultTimeZone)
+		if err != nil {
+			return time.UTC
+		}
+	}
+	return loc
+}
+
+func (n *Notifier) reminderMessage(r reminder) (Message, error) {
+	msg, err := n.templates.Render(KindReminder, r.subscriber.Locale, TemplateData{
+		SubscriberName: r.subscriber.Name,
+		CelebrantName:  r.celebrant.Name,
+		Age:            birthday.Age(r.celebrant.DateOfBirth, r.birthday),
+		DaysUntil:      r.offset,
+		Date:           r.birthday,
+	})
+	if err != nil {
+		return Message{}, err
 	}
-	return nil
+	msg.Celebrant = r.celebrant
+	return msg, nil
+}
+
+// digestMessage собирает все напоминания подписчика за день в одно сообщение, ближайшие дни рождения первыми.
+func (n *Notifier) digestMessage(reminders []reminder) (Message, error) {
+	sorted := make([]reminder, len(reminders))
+	copy(sorted, reminders)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].offset < sorted[j].offset
+	})
+
+	subscriber := sorted[0].subscriber
+	data := TemplateData{SubscriberName: subscriber.Name}
+	for _, r := range sorted {
+		data.Birthdays = append(data.Birthdays, TemplateData{
+			CelebrantName: r.celebrant.Name,
+			Age:           birthday.Age(r.celebrant.DateOfBirth, r.birthday),
+			DaysUntil:     r.offset,
+			Date:          r.birthday,
+		})
+	}
+	return n.templates.Render(KindDigest, subscriber.Locale, data)
+}
+
+func eventID(subscriberID int, dedupeKey string) string {
+	return fmt.Sprintf("%d:%s", subscriberID, dedupeKey)
+}
+
+func reminderRecord(r reminder) notification.Notification {
+	return notification.Notification{
+		SubscriberID: r.subscriber.ID,
+		CelebrantID:  r.celebrant.ID,
+		BirthdayYear: r.birthday.Year(),
+		OffsetDays:   r.offset,
+		Kind:         notification.KindReminder,
+		DedupeKey:    fmt.Sprintf("reminder:%d:%d:%d", r.celebrant.ID, r.birthday.Year(), r.offset),
+	}
+}
+
+// digestRecord - дайджест из напоминаний reminders. Одно напоминание входит только в один дайджест,
+// поэтому ключ, кроме дня, включает хеш их набора: дополнительный дайджест того же дня получает свой ключ.
+func digestRecord(reminders []reminder) notification.Notification {
+	r := reminders[0]
+	today := r.birthday.AddDate(0, 0, -r.offset)
+	return notification.Notification{
+		SubscriberID: r.subscriber.ID,
+		BirthdayYear: today.Year(),
+		Kind:         notification.KindDigest,
+		DedupeKey:    "digest:" + today.Format("2006-01-02") + ":" + digestHash(reminders),
+		Payload:      notification.Payload{Reminders: reminderKeys(reminders)},
+	}
+}
+
+// digestEntry - отметка о том, что напоминание r уже вошло в дайджест. Отметки ничего не отправляют
+// и не попадают в очередь повторов: для канала digestEntryChannel нет отправителя.
+func digestEntry(r reminder) notification.Notification {
+	record := reminderRecord(r)
+	record.Kind = notification.KindDigestEntry
+	record.Channel = digestEntryChannel
+	return record
+}
 
+// reminderKeys возвращает отсортированные ключи напоминаний.
+func reminderKeys(reminders []reminder) []string {
+	keys := make([]string, 0, len(reminders))
+	for _, r := range reminders {
+		keys = append(keys, reminderRecord(r).DedupeKey)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// digestHash - устойчивый к порядку хеш набора напоминаний дайджеста.
+func digestHash(reminders []reminder) string {
+	sum := sha256.Sum256([]byte(strings.Join(reminderKeys(reminders), "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// deliver отправляет сообщение подписчику по всем подходящим каналам,
+// фиксируя каждую попытку в журнале доставки по образцу base.
+func (n *Notifier) deliver(subscriber user.User, base notification.Notification, msg Message) {
+	msg.Kind = base.Kind
+	msg.EventID = eventID(subscriber.ID, base.DedupeKey)
+
+	for _, sender := range n.senders.Senders() {
+		if !subscriber.ChannelEnabled(sender.Channel()) || !sender.Accepts(subscriber) {
+			continue
+		}
+
+		record := base
+		record.Channel = sender.Channel()
+		record.Payload = notification.Payload{Subject: msg.Subject, Body: msg.Body, HTMLBody: msg.HTMLBody, Reminders: base.Payload.Reminders}
+
+		claimed, err := n.notificationRepo.Claim(&record)
+		if err != nil {
+			log.Printf("Error claiming %s notification for user ID %d: %v\n", sender.Channel(), subscriber.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := sender.Send(subscriber, msg); err != nil {
+			log.Printf("Error sending %s notification to user ID %d: %v\n", sender.Channel(), subscriber.ID, err)
+			n.recordFailure(record, err)
+			continue
+		}
+		if err := n.notificationRepo.MarkSent(record.ID); err != nil {
+			log.Printf("Error recording sent notification ID %d: %v\n", record.ID, err)
+		}
+		log.Printf("Sent %s %s notification to user ID %d\n", sender.Channel(), record.Kind, subscriber.ID)
+	}
 }
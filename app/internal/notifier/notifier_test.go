@@ -0,0 +1,442 @@
+package notifier
+
+import (
+	mock_notifier "birthdayReminder/internal/notifier/mocks"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/subscription"
+	"birthdayReminder/internal/repository/user"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sentMessage struct {
+	recipient user.User
+	msg       Message
+}
+
+type memorySender struct {
+	channel string
+	err     error
+	sent    []sentMessage
+}
+
+func (s *memorySender) Channel() string {
+	return s.channel
+}
+
+func (s *memorySender) Accepts(recipient user.User) bool {
+	return recipient.Email != ""
+}
+
+func (s *memorySender) Send(recipient user.User, msg Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, sentMessage{recipient: recipient, msg: msg})
+	return nil
+}
+
+func claimAs(id int) func(n *notification.Notification) (bool, error) {
+	return func(n *notification.Notification) (bool, error) {
+		n.ID = id
+		return true, nil
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	first := &memorySender{channel: "email"}
+	replacement := &memorySender{channel: "email"}
+	other := &memorySender{channel: "telegram"}
+
+	registry := NewRegistry(first, other)
+	registry.Register(replacement)
+
+	senders := registry.Senders()
+	assert.Len(t, senders, 2)
+	assert.Same(t, replacement, senders[0])
+	assert.Same(t, other, senders[1])
+
+	got, ok := registry.Get("telegram")
+	assert.True(t, ok)
+	assert.Same(t, other, got)
+
+	_, ok = registry.Get("webhook")
+	assert.False(t, ok)
+}
+
+func TestDueReminders(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC)
+	details := func(timeZone string, notifyHour int, dob time.Time, offsets ...int) subscription.Details {
+		return subscription.Details{
+			Subscription: subscription.Subscription{Offsets: offsets},
+			Subscriber:   user.User{ID: 2, TimeZone: timeZone, NotifyHour: notifyHour},
+			Celebrant:    user.User{ID: 1, DateOfBirth: dob},
+		}
+	}
+	quiet := func(d subscription.Details, start, end int) subscription.Details {
+		d.Subscriber.QuietStart, d.Subscriber.QuietEnd = start, end
+		return d
+	}
+	snoozed := func(d subscription.Details, until time.Time) subscription.Details {
+		d.SnoozedUntil = &until
+		return d
+	}
+
+	testCases := []struct {
+		name             string
+		subscription     subscription.Details
+		expectedOffsets  []int
+		expectedBirthday time.Time
+	}{
+		{
+			name:             "Birthday tomorrow with default offset",
+			subscription:     details("UTC", 9, time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC), 1),
+			expectedOffsets:  []int{1},
+			expectedBirthday: time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "Birthday today",
+			subscription:     details("UTC", 9, time.Date(1990, time.October, 17, 0, 0, 0, 0, time.UTC), 7, 1, 0),
+			expectedOffsets:  []int{0},
+			expectedBirthday: time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "Week ahead",
+			subscription:     details("UTC", 9, time.Date(1985, time.October, 24, 0, 0, 0, 0, time.UTC), 7, 1),
+			expectedOffsets:  []int{7},
+			expectedBirthday: time.Date(2026, time.October, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:            "Not due for any offset",
+			subscription:    details("UTC", 9, time.Date(1985, time.October, 20, 0, 0, 0, 0, time.UTC), 7, 1, 0),
+			expectedOffsets: nil,
+		},
+		{
+			name:            "Preferred local hour not reached yet",
+			subscription:    details("UTC", 11, time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC), 1),
+			expectedOffsets: nil,
+		},
+		{
+			name:            "Quiet hours postpone the reminder",
+			subscription:    quiet(details("UTC", 9, time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC), 1), 9, 11),
+			expectedOffsets: nil,
+		},
+		{
+			name:             "Quiet hours over midnight leave the day free",
+			subscription:     quiet(details("UTC", 9, time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC), 1), 22, 8),
+			expectedOffsets:  []int{1},
+			expectedBirthday: time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:            "Snoozed subscription is skipped",
+			subscription:    snoozed(details("UTC", 9, time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC), 1), time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)),
+			expectedOffsets: nil,
+		},
+		{
+			name:             "Snooze ends today",
+			subscription:     snoozed(details("UTC", 9, time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC), 1), time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)),
+			expectedOffsets:  []int{1},
+			expectedBirthday: time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "Subscriber already in the next day",
+			subscription:     details("Pacific/Kiritimati", 0, time.Date(1990, time.October, 19, 0, 0, 0, 0, time.UTC), 1),
+			expectedOffsets:  []int{1},
+			expectedBirthday: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:            "Subscriber still in the previous day",
+			subscription:    details("Pacific/Pago_Pago", 0, time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC), 1),
+			expectedOffsets: nil,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var offsets []int
+			for _, r := range dueReminders([]subscription.Details{tt.subscription}, now) {
+				offsets = append(offsets, r.offset)
+				assert.Equal(t, tt.expectedBirthday.Format("2006-01-02"), r.birthday.Format("2006-01-02"))
+			}
+			assert.Equal(t, tt.expectedOffsets, offsets)
+		})
+	}
+}
+
+func TestDueRemindersForLeapling(t *testing.T) {
+	now := time.Date(2027, time.February, 27, 10, 0, 0, 0, time.UTC)
+	leapling := func(policy string) subscription.Details {
+		return subscription.Details{
+			Subscription: subscription.Subscription{Offsets: []int{2, 1}},
+			Subscriber:   user.User{ID: 2, TimeZone: "UTC"},
+			Celebrant:    user.User{ID: 1, DateOfBirth: time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC), LeapPolicy: policy},
+		}
+	}
+
+	reminders := dueReminders([]subscription.Details{leapling("feb28")}, now)
+	assert.Len(t, reminders, 1)
+	assert.Equal(t, 1, reminders[0].offset)
+	assert.Equal(t, "2027-02-28", reminders[0].birthday.Format("2006-01-02"))
+
+	reminders = dueReminders([]subscription.Details{leapling("mar1")}, now)
+	assert.Len(t, reminders, 1)
+	assert.Equal(t, 2, reminders[0].offset)
+	assert.Equal(t, "2027-03-01", reminders[0].birthday.Format("2006-01-02"))
+}
+
+func TestSendBirthdayNotifications(t *testing.T) {
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	celebrant := user.User{ID: 1, Name: "Anna", Email: "anna@example.com", DateOfBirth: tomorrow.AddDate(-30, 0, 0)}
+	subscriptions := []subscription.Details{
+		{
+			Subscription: subscription.Subscription{ID: 1, UserID: 2, RelatedUserID: 1, Offsets: []int{1}},
+			Subscriber:   user.User{ID: 2, Name: "Ivan", Email: "ivan@example.com", TimeZone: "UTC"},
+			Celebrant:    celebrant,
+		},
+		{
+			Subscription: subscription.Subscription{ID: 2, UserID: 3, RelatedUserID: 1, Offsets: []int{1}},
+			Subscriber:   user.User{ID: 3, Name: "Olga", TimeZone: "UTC"},
+			Celebrant:    celebrant,
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		setupMock    func(mockSubscriptionRepo *mock_notifier.MockSubscriptionRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository)
+		email        *memorySender
+		telegram     *memorySender
+		expectedSent int
+	}{
+		{
+			name: "Error fetching subscriptions",
+			setupMock: func(mockSubscriptionRepo *mock_notifier.MockSubscriptionRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockSubscriptionRepo.EXPECT().GetAllDetails().Return(nil, errors.New("db error"))
+			},
+			email:        &memorySender{channel: "email"},
+			telegram:     &memorySender{channel: "telegram"},
+			expectedSent: 0,
+		},
+		{
+			name: "No reminders due",
+			setupMock: func(mockSubscriptionRepo *mock_notifier.MockSubscriptionRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockSubscriptionRepo.EXPECT().GetAllDetails().Return(nil, nil)
+			},
+			email:        &memorySender{channel: "email"},
+			telegram:     &memorySender{channel: "telegram"},
+			expectedSent: 0,
+		},
+		{
+			name: "Delivers through every accepting channel",
+			setupMock: func(mockSubscriptionRepo *mock_notifier.MockSubscriptionRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockSubscriptionRepo.EXPECT().GetAllDetails().Return(subscriptions, nil)
+				mockNotificationRepo.EXPECT().Claim(gomock.Any()).Times(2).DoAndReturn(claimAs(10))
+				mockNotificationRepo.EXPECT().MarkSent(10).Times(2).Return(nil)
+			},
+			email:        &memorySender{channel: "email"},
+			telegram:     &memorySender{channel: "telegram"},
+			expectedSent: 1,
+		},
+		{
+			name: "Skips already delivered reminders",
+			setupMock: func(mockSubscriptionRepo *mock_notifier.MockSubscriptionRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockSubscriptionRepo.EXPECT().GetAllDetails().Return(subscriptions, nil)
+				mockNotificationRepo.EXPECT().Claim(gomock.Any()).Times(2).Return(false, nil)
+			},
+			email:        &memorySender{channel: "email"},
+			telegram:     &memorySender{channel: "telegram"},
+			expectedSent: 0,
+		},
+		{
+			name: "Failing channel does not block others",
+			setupMock: func(mockSubscriptionRepo *mock_notifier.MockSubscriptionRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockSubscriptionRepo.EXPECT().GetAllDetails().Return(subscriptions, nil)
+				gomock.InOrder(
+					mockNotificationRepo.EXPECT().Claim(gomock.Any()).DoAndReturn(claimAs(10)),
+					mockNotificationRepo.EXPECT().MarkFailed(10, gomock.Any(), gomock.Any()).Return(nil),
+				)
+				gomock.InOrder(
+					mockNotificationRepo.EXPECT().Claim(gomock.Any()).DoAndReturn(claimAs(11)),
+					mockNotificationRepo.EXPECT().MarkSent(11).Return(nil),
+				)
+			},
+			email:        &memorySender{channel: "email", err: errors.New("smtp down")},
+			telegram:     &memorySender{channel: "telegram"},
+			expectedSent: 1,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSubscriptionRepo := mock_notifier.NewMockSubscriptionRepository(ctrl)
+			mockNotificationRepo := mock_notifier.NewMockNotificationRepository(ctrl)
+			tt.setupMock(mockSubscriptionRepo, mockNotificationRepo)
+
+			n := New(nil, mockSubscriptionRepo, mockNotificationRepo, nil, NewRegistry(tt.email, tt.telegram), nil, DefaultConfig())
+			n.SendBirthdayNotifications()
+
+			assert.Len(t, tt.telegram.sent, tt.expectedSent)
+			if tt.email.err == nil {
+				assert.Len(t, tt.email.sent, tt.expectedSent)
+			}
+			for _, sent := range tt.telegram.sent {
+				assert.Equal(t, 2, sent.recipient.ID)
+				assert.Equal(t, celebrant.ID, sent.msg.Celebrant.ID)
+				assert.Contains(t, sent.msg.Body, "Завтра день рождения у "+celebrant.Name)
+			}
+		})
+	}
+}
+
+func TestDeliverSkipsDisabledChannels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockNotificationRepo := mock_notifier.NewMockNotificationRepository(ctrl)
+	mockNotificationRepo.EXPECT().Claim(gomock.Any()).DoAndReturn(func(record *notification.Notification) (bool, error) {
+		assert.Equal(t, "telegram", record.Channel)
+		record.ID = 10
+		return true, nil
+	})
+	mockNotificationRepo.EXPECT().MarkSent(10).Return(nil)
+
+	email := &memorySender{channel: "email"}
+	telegram := &memorySender{channel: "telegram"}
+	n := New(nil, nil, mockNotificationRepo, nil, NewRegistry(email, telegram), nil, DefaultConfig())
+
+	subscriber := user.User{ID: 2, Email: "ivan@example.com", DisabledChannels: []string{"email"}}
+	n.deliver(subscriber, notification.Notification{Kind: notification.KindReminder, DedupeKey: "reminder:1:2026:1"}, Message{Body: "body"})
+
+	assert.Empty(t, email.sent)
+	assert.Len(t, telegram.sent, 1)
+}
+
+// memoryClaims повторяет семантику NotificationRepository.Claim: запись с тем же подписчиком,
+// ключом и каналом резервируется только один раз.
+type memoryClaims struct {
+	records map[string]notification.Notification
+}
+
+func newMemoryClaims() *memoryClaims {
+	return &memoryClaims{records: make(map[string]notification.Notification)}
+}
+
+func (m *memoryClaims) claim(n *notification.Notification) (bool, error) {
+	key := fmt.Sprintf("%d/%s/%s", n.SubscriberID, n.DedupeKey, n.Channel)
+	if _, ok := m.records[key]; ok {
+		return false, nil
+	}
+	n.ID = len(m.records) + 1
+	m.records[key] = *n
+	return true, nil
+}
+
+// byKind возвращает зарезервированные записи вида kind.
+func (m *memoryClaims) byKind(kind string) []notification.Notification {
+	var records []notification.Notification
+	for _, n := range m.records {
+		if n.Kind == kind {
+			records = append(records, n)
+		}
+	}
+	return records
+}
+
+func TestSendDigestNotifications(t *testing.T) {
+	now := time.Now().UTC()
+	subscriber := user.User{ID: 2, Name: "Ivan", Email: "ivan@example.com", TimeZone: "UTC", DeliveryMode: user.DeliveryDigest}
+	anna := user.User{ID: 1, Name: "Anna", DateOfBirth: now.AddDate(-30, 0, 1)}
+	oleg := user.User{ID: 3, Name: "Oleg", DateOfBirth: now.AddDate(-25, 0, 0)}
+	subscriptions := []subscription.Details{
+		{Subscription: subscription.Subscription{ID: 1, Offsets: []int{1}}, Subscriber: subscriber, Celebrant: anna},
+		{Subscription: subscription.Subscription{ID: 2, Offsets: []int{7, 0}}, Subscriber: subscriber, Celebrant: oleg},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	claims := newMemoryClaims()
+	mockSubscriptionRepo := mock_notifier.NewMockSubscriptionRepository(ctrl)
+	mockNotificationRepo := mock_notifier.NewMockNotificationRepository(ctrl)
+	mockSubscriptionRepo.EXPECT().GetAllDetails().Return(subscriptions, nil)
+	mockNotificationRepo.EXPECT().Claim(gomock.Any()).DoAndReturn(claims.claim).Times(3)
+	mockNotificationRepo.EXPECT().MarkSent(gomock.Any()).Return(nil).Times(3)
+
+	email := &memorySender{channel: "email"}
+	n := New(nil, mockSubscriptionRepo, mockNotificationRepo, nil, NewRegistry(email), nil, DefaultConfig())
+	n.SendBirthdayNotifications()
+
+	digests := claims.byKind(notification.KindDigest)
+	require.Len(t, digests, 1)
+	assert.Equal(t, "email", digests[0].Channel)
+	assert.True(t, strings.HasPrefix(digests[0].DedupeKey, "digest:"+now.Format("2006-01-02")+":"), digests[0].DedupeKey)
+	assert.Zero(t, digests[0].CelebrantID)
+
+	// каждое напоминание отмечено как вошедшее в дайджест
+	entries := claims.byKind(notification.KindDigestEntry)
+	assert.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, digestEntryChannel, entry.Channel)
+		assert.True(t, strings.HasPrefix(entry.DedupeKey, "reminder:"), entry.DedupeKey)
+	}
+
+	assert.Len(t, email.sent, 1)
+	body := email.sent[0].msg.Body
+	assert.Contains(t, body, "- Oleg - сегодня")
+	assert.Contains(t, body, "- Anna - завтра")
+	assert.Less(t, strings.Index(body, "Oleg"), strings.Index(body, "Anna"))
+}
+
+func TestSendDigestNotificationsLaterSameDay(t *testing.T) {
+	now := time.Now().UTC()
+	subscriber := user.User{ID: 2, Name: "Ivan", Email: "ivan@example.com", TimeZone: "UTC", DeliveryMode: user.DeliveryDigest}
+	anna := user.User{ID: 1, Name: "Anna", DateOfBirth: now.AddDate(-30, 0, 1)}
+	oleg := user.User{ID: 3, Name: "Oleg", DateOfBirth: now.AddDate(-25, 0, 0)}
+	petr := user.User{ID: 4, Name: "Petr", DateOfBirth: now.AddDate(-40, 0, 2)}
+	annaSub := subscription.Details{Subscription: subscription.Subscription{ID: 1, Offsets: []int{1}}, Subscriber: subscriber, Celebrant: anna}
+	olegSub := subscription.Details{Subscription: subscription.Subscription{ID: 2, Offsets: []int{0}}, Subscriber: subscriber, Celebrant: oleg}
+	petrSub := subscription.Details{Subscription: subscription.Subscription{ID: 3, Offsets: []int{2}}, Subscriber: subscriber, Celebrant: petr}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	claims := newMemoryClaims()
+	mockSubscriptionRepo := mock_notifier.NewMockSubscriptionRepository(ctrl)
+	mockNotificationRepo := mock_notifier.NewMockNotificationRepository(ctrl)
+	gomock.InOrder(
+		mockSubscriptionRepo.EXPECT().GetAllDetails().Return([]subscription.Details{annaSub, petrSub}, nil),
+		// подписка на Oleg оформлена после утренней рассылки
+		mockSubscriptionRepo.EXPECT().GetAllDetails().Return([]subscription.Details{annaSub, petrSub, olegSub}, nil),
+		// ничего нового не появилось
+		mockSubscriptionRepo.EXPECT().GetAllDetails().Return([]subscription.Details{annaSub, petrSub, olegSub}, nil),
+		// отписка от Petr не должна приводить к повторной отправке остальных
+		mockSubscriptionRepo.EXPECT().GetAllDetails().Return([]subscription.Details{annaSub, olegSub}, nil),
+	)
+	mockNotificationRepo.EXPECT().Claim(gomock.Any()).DoAndReturn(claims.claim).AnyTimes()
+	mockNotificationRepo.EXPECT().MarkSent(gomock.Any()).Return(nil).AnyTimes()
+
+	email := &memorySender{channel: "email"}
+	n := New(nil, mockSubscriptionRepo, mockNotificationRepo, nil, NewRegistry(email), nil, DefaultConfig())
+	for i := 0; i < 4; i++ {
+		n.SendBirthdayNotifications()
+	}
+
+	require.Len(t, email.sent, 2)
+	first, second := email.sent[0].msg.Body, email.sent[1].msg.Body
+	assert.Contains(t, first, "Anna")
+	assert.Contains(t, first, "Petr")
+	assert.NotContains(t, first, "Oleg")
+	// дополнительный дайджест содержит только новое напоминание
+	assert.Contains(t, second, "- Oleg - сегодня")
+	assert.NotContains(t, second, "Anna")
+	assert.NotContains(t, second, "Petr")
+	assert.Len(t, claims.byKind(notification.KindDigest), 2)
+}
@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/webpush"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	ChannelPush = "push"
+
+	// pushTTL - напоминание неактуально через сутки, дольше push-сервису хранить его незачем.
+	pushTTL = 24 * time.Hour
+)
+
+// PushPayload - расшифрованное сообщение, которое получает service worker браузера.
+type PushPayload struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Kind  string `json:"kind"`
+	// Tag - идентификатор события: service worker заменяет им повторно доставленное уведомление.
+	Tag         string `json:"tag"`
+	CelebrantID int    `json:"celebrant_id,omitempty"`
+}
+
+// PushSender доставляет уведомления на все устройства получателя через Web Push.
+// Доставка учитывается по каждому устройству, поэтому повторная отправка не дублирует
+// сообщение на устройствах, которые его уже получили. Подписки, которые push-сервис признал истёкшими, удаляются.
+type PushSender struct {
+	repo   PushRepository
+	client PushClient
+}
+
+func NewPushSender(repo PushRepository, client PushClient) *PushSender {
+	return &PushSender{repo: repo, client: client}
+}
+
+func (s *PushSender) Channel() string {
+	return ChannelPush
+}
+
+func (s *PushSender) Accepts(recipient user.User) bool {
+	ok, err := s.repo.HasSubscriptions(recipient.ID)
+	if err != nil {
+		// не теряем событие: ошибка повторится в Send и попадёт в очередь повторов
+		log.Printf("Error checking push subscriptions for user ID %d: %v\n", recipient.ID, err)
+		return true
+	}
+	return ok
+}
+
+func (s *PushSender) Send(recipient user.User, msg Message) error {
+	subscriptions, err := s.repo.GetPending(recipient.ID, msg.EventID)
+	if err != nil {
+		return err
+	}
+
+	payload, err := pushPayload(msg)
+	if err != nil {
+		return err
+	}
+	opts := webpush.Options{TTL: pushTTL, Topic: pushTopic(msg.EventID)}
+
+	var errs []error
+	for _, sub := range subscriptions {
+		err := s.client.Send(webpush.Subscription{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}, payload, opts)
+		if errors.Is(err, webpush.ErrSubscriptionGone) {
+			log.Printf("Removing expired push subscription ID %d of user ID %d\n", sub.ID, recipient.ID)
+			if err := s.repo.DeleteByEndpoint(sub.Endpoint); err != nil {
+				log.Printf("Error removing push subscription ID %d: %v\n", sub.ID, err)
+			}
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("push subscription %d: %w", sub.ID, err))
+			continue
+		}
+		if err := s.repo.RecordDelivery(sub.ID, msg.EventID); err != nil {
+			log.Printf("Error recording push delivery to subscription ID %d: %v\n", sub.ID, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pushPayload укорачивает текст, если сообщение не помещается в одну запись Web Push.
+func pushPayload(msg Message) ([]byte, error) {
+	p := PushPayload{Title: msg.Subject, Body: msg.Body, Kind: msg.Kind, Tag: msg.EventID, CelebrantID: msg.Celebrant.ID}
+	for {
+		payload, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		if len(payload) <= webpush.MaxPayloadSize || p.Body == "" {
+			return payload, nil
+		}
+		body := []rune(p.Body)
+		p.Body = string(body[:len(body)*3/4]) + "…"
+		if len(body) < 4 {
+			p.Body = ""
+		}
+	}
+}
+
+// pushTopic - топик из RFC 8030: не больше 32 символов base64url.
+func pushTopic(eventID string) string {
+	if eventID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(eventID))
+	return base64.RawURLEncoding.EncodeToString(sum[:])[:32]
+}
@@ -0,0 +1,136 @@
+package notifier
+
+import (
+	mock_notifier "birthdayReminder/internal/notifier/mocks"
+	"birthdayReminder/internal/repository/push"
+	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/webpush"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestPushSender(t *testing.T) {
+	// локальный push-сервис: первое устройство принимает сообщение, второе уже отписалось
+	var delivered []string
+	pushService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "aes128gcm", r.Header.Get("Content-Encoding"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "vapid t="))
+		delivered = append(delivered, r.URL.Path)
+		if r.URL.Path == "/push/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer pushService.Close()
+
+	public, private, err := webpush.GenerateVAPIDKeys()
+	require.NoError(t, err)
+	vapid, err := webpush.NewVAPID(public, private, "mailto:admin@example.com")
+	require.NoError(t, err)
+
+	browserKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p256dh := base64.RawURLEncoding.EncodeToString(browserKey.PublicKey().Bytes())
+	auth := base64.RawURLEncoding.EncodeToString(make([]byte, 16))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_notifier.NewMockPushRepository(ctrl)
+	repo.EXPECT().HasSubscriptions(2).Return(true, nil)
+	repo.EXPECT().GetPending(2, "2:reminder:1:2026:1").Return([]push.Subscription{
+		{ID: 1, UserID: 2, Endpoint: pushService.URL + "/push/laptop", P256dh: p256dh, Auth: auth},
+		{ID: 2, UserID: 2, Endpoint: pushService.URL + "/push/gone", P256dh: p256dh, Auth: auth},
+	}, nil)
+	repo.EXPECT().RecordDelivery(1, "2:reminder:1:2026:1").Return(nil)
+	repo.EXPECT().DeleteByEndpoint(pushService.URL + "/push/gone").Return(nil)
+
+	sender := NewPushSender(repo, webpush.NewClient(vapid, true))
+	recipient := user.User{ID: 2}
+	require.True(t, sender.Accepts(recipient))
+
+	err = sender.Send(recipient, Message{Subject: "Напоминание", Body: "Завтра день рождения у Anna!", Kind: KindReminder, EventID: "2:reminder:1:2026:1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/push/laptop", "/push/gone"}, delivered)
+}
+
+func TestPushSenderRetryDoesNotRepeatDeliveredDevices(t *testing.T) {
+	// телефон временно недоступен, ноутбук принимает сообщение с первой попытки
+	var delivered []string
+	phoneDown := true
+	pushService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered = append(delivered, r.URL.Path)
+		if r.URL.Path == "/push/phone" && phoneDown {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer pushService.Close()
+
+	public, private, err := webpush.GenerateVAPIDKeys()
+	require.NoError(t, err)
+	vapid, err := webpush.NewVAPID(public, private, "mailto:admin@example.com")
+	require.NoError(t, err)
+
+	browserKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	p256dh := base64.RawURLEncoding.EncodeToString(browserKey.PublicKey().Bytes())
+	auth := base64.RawURLEncoding.EncodeToString(make([]byte, 16))
+	laptop := push.Subscription{ID: 1, UserID: 2, Endpoint: pushService.URL + "/push/laptop", P256dh: p256dh, Auth: auth}
+	phone := push.Subscription{ID: 2, UserID: 2, Endpoint: pushService.URL + "/push/phone", P256dh: p256dh, Auth: auth}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// учёт доставок в памяти вместо push_deliveries
+	recorded := map[int]bool{}
+	repo := mock_notifier.NewMockPushRepository(ctrl)
+	repo.EXPECT().GetPending(2, "event").Times(2).DoAndReturn(func(userID int, eventID string) ([]push.Subscription, error) {
+		var pending []push.Subscription
+		for _, sub := range []push.Subscription{laptop, phone} {
+			if !recorded[sub.ID] {
+				pending = append(pending, sub)
+			}
+		}
+		return pending, nil
+	})
+	repo.EXPECT().RecordDelivery(gomock.Any(), "event").Times(2).DoAndReturn(func(subscriptionID int, eventID string) error {
+		recorded[subscriptionID] = true
+		return nil
+	})
+
+	sender := NewPushSender(repo, webpush.NewClient(vapid, true))
+	msg := Message{Subject: "Напоминание", Kind: KindReminder, EventID: "event"}
+
+	require.Error(t, sender.Send(user.User{ID: 2}, msg))
+	phoneDown = false
+	require.NoError(t, sender.Send(user.User{ID: 2}, msg))
+
+	assert.Equal(t, []string{"/push/laptop", "/push/phone", "/push/phone"}, delivered)
+}
+
+func TestPushPayloadTruncatesLongBody(t *testing.T) {
+	payload, err := pushPayload(Message{Subject: "Сводка", Body: strings.Repeat("день рождения ", 1000), EventID: "2:weekly:2026-10-12"})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(payload), webpush.MaxPayloadSize)
+	assert.True(t, utf8.Valid(payload))
+	assert.Contains(t, string(payload), `…","kind"`)
+}
+
+func TestPushTopic(t *testing.T) {
+	topic := pushTopic("2:reminder:1:2026:1")
+	assert.Len(t, topic, 32)
+	assert.Equal(t, topic, pushTopic("2:reminder:1:2026:1"))
+	assert.Empty(t, pushTopic(""))
+}
@@ -0,0 +1,248 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/birthday"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/subscription"
+	"birthdayReminder/internal/repository/user"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const retryBatchSize = 100
+
+// errChannelDisabled - получатель отключил канал, повторять отправку больше не нужно.
+// errSubscriptionInactive - подписку удалили или приостановили после первой попытки.
+var (
+	errChannelDisabled      = errors.New("channel disabled by recipient")
+	errNotAccepted          = errors.New("recipient not accepted by channel")
+	errSubscriptionInactive = errors.New("subscription removed or snoozed")
+)
+
+// quietHoursError - у получателя период тишины, отправку нужно отложить до until.
+type quietHoursError struct {
+	until time.Time
+}
+
+func (e *quietHoursError) Error() string {
+	return "recipient quiet hours until " + e.until.Format(time.RFC3339)
+}
+
+// quietHoursEnd возвращает ближайшее окончание периода тишины пользователя после now.
+func quietHoursEnd(u user.User, now time.Time) time.Time {
+	local := now.In(loadLocation(u.TimeZone))
+	end := time.Date(local.Year(), local.Month(), local.Day(), u.QuietEnd, 0, 0, 0, local.Location())
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// RetryPolicy описывает экспоненциальную задержку между повторными отправками.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 6,
+	BaseDelay:   time.Minute,
+	MaxDelay:    6 * time.Hour,
+}
+
+// Backoff возвращает задержку перед следующей попыткой после attempts неудачных.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// recordFailure ставит напоминание в очередь повторов или переводит его в dead-letter.
+func (n *Notifier) recordFailure(record notification.Notification, sendErr error) {
+	attempts := record.Attempts + 1
+	if attempts >= n.retry.MaxAttempts {
+		log.Printf("Notification ID %d failed %d times, moving to dead letter\n", record.ID, attempts)
+		if err := n.notificationRepo.MarkDead(record.ID, sendErr); err != nil {
+			log.Printf("Error recording dead notification ID %d: %v\n", record.ID, err)
+		}
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(n.retry.Backoff(attempts))
+	if err := n.notificationRepo.MarkFailed(record.ID, sendErr, nextAttemptAt); err != nil {
+		log.Printf("Error recording failed notification ID %d: %v\n", record.ID, err)
+	}
+}
+
+// RetryFailedNotifications повторно отправляет напоминания из очереди, время которых наступило.
+func (n *Notifier) RetryFailedNotifications() {
+	records, err := n.notificationRepo.ClaimDue(retryBatchSize)
+	if err != nil {
+		log.Println("Error fetching notifications for retry:", err)
+		return
+	}
+
+	for _, record := range records {
+		err := n.retryNotification(record)
+		var quiet *quietHoursError
+		switch {
+		case errors.Is(err, errChannelDisabled), errors.Is(err, errNotAccepted), errors.Is(err, errSubscriptionInactive):
+			log.Printf("Skipping notification ID %d: %v\n", record.ID, err)
+			if err := n.notificationRepo.MarkSkipped(record.ID); err != nil {
+				log.Printf("Error skipping notification ID %d: %v\n", record.ID, err)
+			}
+			continue
+		case errors.As(err, &quiet):
+			if err := n.notificationRepo.Postpone(record.ID, quiet.until); err != nil {
+				log.Printf("Error postponing notification ID %d: %v\n", record.ID, err)
+			}
+			continue
+		case err != nil:
+			log.Printf("Error retrying notification ID %d (attempt %d): %v\n", record.ID, record.Attempts+1, err)
+			n.recordFailure(record, err)
+			continue
+		}
+		if err := n.notificationRepo.MarkSent(record.ID); err != nil {
+			log.Printf("Error recording sent notification ID %d: %v\n", record.ID, err)
+		}
+		log.Printf("Retried notification ID %d successfully\n", record.ID)
+	}
+}
+
+func (n *Notifier) retryNotification(record notification.Notification) error {
+	sender, ok := n.senders.Get(record.Channel)
+	if !ok {
+		return fmt.Errorf("channel %q is not registered", record.Channel)
+	}
+
+	subscriber, err := n.userRepo.GetUserByID(record.SubscriberID)
+	if err != nil {
+		return fmt.Errorf("error fetching subscriber: %v", err)
+	}
+
+	// настройки могли измениться после первой попытки
+	if !subscriber.ChannelEnabled(record.Channel) {
+		return errChannelDisabled
+	}
+	// например, адрес email не подтвержден
+	if !sender.Accepts(*subscriber) {
+		return errNotAccepted
+	}
+	now := time.Now()
+	local := now.In(loadLocation(subscriber.TimeZone))
+	if subscriber.InQuietHours(local.Hour()) {
+		return &quietHoursError{until: quietHoursEnd(*subscriber, now)}
+	}
+
+	msg := Message{
+		Subject:  record.Payload.Subject,
+		Body:     record.Payload.Body,
+		HTMLBody: record.Payload.HTMLBody,
+		Kind:     record.Kind,
+		EventID:  eventID(record.SubscriberID, record.DedupeKey),
+	}
+
+	// у дайджеста нет единственного именинника
+	if record.CelebrantID != 0 {
+		celebrant, err := n.userRepo.GetUserByID(record.CelebrantID)
+		if err != nil {
+			return fmt.Errorf("error fetching celebrant: %v", err)
+		}
+		msg.Celebrant = *celebrant
+	}
+
+	// подписку могли удалить или приостановить после первой попытки
+	switch record.Kind {
+	case notification.KindReminder:
+		c := msg.Celebrant
+		occurrence := birthday.Occurrence(c.DateOfBirth, record.BirthdayYear, birthday.Policy(c.LeapPolicy), local.Location())
+		if err := n.checkSubscription(record.SubscriberID, record.CelebrantID, occurrence.AddDate(0, 0, -record.OffsetDays)); err != nil {
+			return err
+		}
+	case notification.KindDigest:
+		// записи, созданные до учёта состава дайджеста, отправляются как есть
+		if len(record.Payload.Reminders) == 0 {
+			break
+		}
+		reminders, err := n.digestRetryReminders(*subscriber, record, local.Location())
+		if err != nil {
+			return err
+		}
+		rendered, err := n.digestMessage(reminders)
+		if err != nil {
+			return fmt.Errorf("error rendering digest: %v", err)
+		}
+		msg.Subject, msg.Body, msg.HTMLBody = rendered.Subject, rendered.Body, rendered.HTMLBody
+	}
+
+	return sender.Send(*subscriber, msg)
+}
+
+// checkSubscription проверяет, что подписка subscriberID на celebrantID существует и не приостановлена в день day.
+func (n *Notifier) checkSubscription(subscriberID, celebrantID int, day time.Time) error {
+	s, err := n.subscriptionRepo.GetByUsers(subscriberID, celebrantID)
+	if errors.Is(err, subscription.ErrNotFound) {
+		return errSubscriptionInactive
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching subscription: %v", err)
+	}
+	if s.SnoozedOn(day) {
+		return errSubscriptionInactive
+	}
+	return nil
+}
+
+// digestRetryReminders восстанавливает напоминания, из которых был собран дайджест record,
+// исключая те, чьи подписки удалены или приостановлены на день дайджеста.
+func (n *Notifier) digestRetryReminders(subscriber user.User, record notification.Notification, loc *time.Location) ([]reminder, error) {
+	// ключ дайджеста: digest:<день>:<хеш набора>
+	parts := strings.Split(record.DedupeKey, ":")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid digest key %q", record.DedupeKey)
+	}
+	day, err := time.ParseInLocation("2006-01-02", parts[1], loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid digest key %q: %v", record.DedupeKey, err)
+	}
+
+	var reminders []reminder
+	for _, key := range record.Payload.Reminders {
+		var celebrantID, year, offset int
+		if _, err := fmt.Sscanf(key, "reminder:%d:%d:%d", &celebrantID, &year, &offset); err != nil {
+			return nil, fmt.Errorf("invalid digest reminder key %q: %v", key, err)
+		}
+
+		err := n.checkSubscription(subscriber.ID, celebrantID, day)
+		if errors.Is(err, errSubscriptionInactive) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		celebrant, err := n.userRepo.GetUserByID(celebrantID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching celebrant: %v", err)
+		}
+		reminders = append(reminders, reminder{
+			subscriber: subscriber,
+			celebrant:  *celebrant,
+			birthday:   day.AddDate(0, 0, offset),
+			offset:     offset,
+		})
+	}
+	if len(reminders) == 0 {
+		return nil, errSubscriptionInactive
+	}
+	return reminders, nil
+}
@@ -0,0 +1,295 @@
+package notifier
+
+import (
+	mock_notifier "birthdayReminder/internal/notifier/mocks"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/subscription"
+	"birthdayReminder/internal/repository/user"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Minute, MaxDelay: time.Hour}
+
+	testCases := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: time.Minute},
+		{attempts: 2, expected: 2 * time.Minute},
+		{attempts: 3, expected: 4 * time.Minute},
+		{attempts: 6, expected: 32 * time.Minute},
+		{attempts: 7, expected: time.Hour},
+		{attempts: 50, expected: time.Hour},
+	}
+
+	for _, tt := range testCases {
+		assert.Equal(t, tt.expected, policy.Backoff(tt.attempts), "attempts=%d", tt.attempts)
+	}
+}
+
+func TestRetryFailedNotifications(t *testing.T) {
+	subscriber := &user.User{ID: 2, Name: "Ivan", Email: "ivan@example.com"}
+	celebrant := &user.User{ID: 1, Name: "Anna", Email: "anna@example.com"}
+	record := notification.Notification{
+		ID:           7,
+		SubscriberID: subscriber.ID,
+		CelebrantID:  celebrant.ID,
+		Channel:      "email",
+		Payload:      notification.Payload{Subject: "subject", Body: "body"},
+	}
+
+	testCases := []struct {
+		name        string
+		attempts    int
+		maxAttempts int
+		sender      *memorySender
+		setupMock   func(mockUserRepo *mock_notifier.MockUserRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository)
+	}{
+		{
+			name:        "Successful retry",
+			attempts:    1,
+			maxAttempts: 5,
+			sender:      &memorySender{channel: "email"},
+			setupMock: func(mockUserRepo *mock_notifier.MockUserRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockNotificationRepo.EXPECT().MarkSent(7).Return(nil)
+			},
+		},
+		{
+			name:        "Failed retry is rescheduled with backoff",
+			attempts:    2,
+			maxAttempts: 5,
+			sender:      &memorySender{channel: "email", err: errors.New("smtp down")},
+			setupMock: func(mockUserRepo *mock_notifier.MockUserRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockNotificationRepo.EXPECT().MarkFailed(7, gomock.Any(), gomock.Any()).DoAndReturn(func(id int, sendErr error, nextAttemptAt time.Time) error {
+					assert.WithinDuration(t, time.Now().Add(4*time.Minute), nextAttemptAt, time.Second)
+					return nil
+				})
+			},
+		},
+		{
+			name:        "Last attempt moves to dead letter",
+			attempts:    2,
+			maxAttempts: 3,
+			sender:      &memorySender{channel: "email", err: errors.New("smtp down")},
+			setupMock: func(mockUserRepo *mock_notifier.MockUserRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockNotificationRepo.EXPECT().MarkDead(7, gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_notifier.NewMockUserRepository(ctrl)
+			mockNotificationRepo := mock_notifier.NewMockNotificationRepository(ctrl)
+
+			due := record
+			due.Attempts = tt.attempts
+			mockNotificationRepo.EXPECT().ClaimDue(gomock.Any()).Return([]notification.Notification{due}, nil)
+			mockUserRepo.EXPECT().GetUserByID(subscriber.ID).Return(subscriber, nil)
+			mockUserRepo.EXPECT().GetUserByID(celebrant.ID).Return(celebrant, nil)
+			tt.setupMock(mockUserRepo, mockNotificationRepo)
+
+			n := New(mockUserRepo, nil, mockNotificationRepo, nil, NewRegistry(tt.sender), nil, DefaultConfig())
+			n.retry = RetryPolicy{MaxAttempts: tt.maxAttempts, BaseDelay: time.Minute, MaxDelay: time.Hour}
+			n.RetryFailedNotifications()
+
+			if tt.sender.err == nil {
+				assert.Len(t, tt.sender.sent, 1)
+				assert.Equal(t, "body", tt.sender.sent[0].msg.Body)
+				assert.Equal(t, celebrant.ID, tt.sender.sent[0].msg.Celebrant.ID)
+			}
+		})
+	}
+}
+
+func TestRetryHonorsPreferences(t *testing.T) {
+	hour := time.Now().UTC().Hour()
+	record := notification.Notification{ID: 7, SubscriberID: 2, Channel: "email", Payload: notification.Payload{Body: "body"}}
+
+	testCases := []struct {
+		name       string
+		subscriber *user.User
+		setupMock  func(mockNotificationRepo *mock_notifier.MockNotificationRepository)
+	}{
+		{
+			name:       "Channel disabled after the first attempt",
+			subscriber: &user.User{ID: 2, Email: "ivan@example.com", TimeZone: "UTC", DisabledChannels: []string{"email"}},
+			setupMock: func(mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockNotificationRepo.EXPECT().MarkSkipped(7).Return(nil)
+			},
+		},
+		{
+			name:       "Channel no longer accepts the recipient",
+			subscriber: &user.User{ID: 2, TimeZone: "UTC"},
+			setupMock: func(mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockNotificationRepo.EXPECT().MarkSkipped(7).Return(nil)
+			},
+		},
+		{
+			name:       "Quiet hours postpone the retry without spending an attempt",
+			subscriber: &user.User{ID: 2, Email: "ivan@example.com", TimeZone: "UTC", QuietStart: hour, QuietEnd: (hour + 2) % 24},
+			setupMock: func(mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockNotificationRepo.EXPECT().Postpone(7, gomock.Any()).DoAndReturn(func(id int, until time.Time) error {
+					now := time.Now().UTC()
+					expected := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC).Add(2 * time.Hour)
+					assert.True(t, expected.Equal(until), "until=%s", until)
+					return nil
+				})
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_notifier.NewMockUserRepository(ctrl)
+			mockNotificationRepo := mock_notifier.NewMockNotificationRepository(ctrl)
+			mockNotificationRepo.EXPECT().ClaimDue(gomock.Any()).Return([]notification.Notification{record}, nil)
+			mockUserRepo.EXPECT().GetUserByID(2).Return(tt.subscriber, nil)
+			tt.setupMock(mockNotificationRepo)
+
+			email := &memorySender{channel: "email"}
+			n := New(mockUserRepo, nil, mockNotificationRepo, nil, NewRegistry(email), nil, DefaultConfig())
+			n.RetryFailedNotifications()
+
+			assert.Empty(t, email.sent)
+		})
+	}
+}
+
+func TestRetrySkipsInactiveSubscriptions(t *testing.T) {
+	// напоминание за 3 дня до 13 марта уходило 10 марта, повтор идёт позже
+	snoozedUntil := time.Date(2026, time.March, 12, 0, 0, 0, 0, time.UTC)
+	subscriber := &user.User{ID: 2, Name: "Ivan", Email: "ivan@example.com", TimeZone: "UTC"}
+	celebrant := &user.User{ID: 1, Name: "Anna", DateOfBirth: time.Date(1990, time.March, 13, 0, 0, 0, 0, time.UTC)}
+	record := notification.Notification{
+		ID:           7,
+		SubscriberID: subscriber.ID,
+		CelebrantID:  celebrant.ID,
+		Kind:         notification.KindReminder,
+		BirthdayYear: 2026,
+		OffsetDays:   3,
+		Channel:      "email",
+		Payload:      notification.Payload{Body: "body"},
+	}
+
+	testCases := []struct {
+		name      string
+		setupMock func(mockSubscriptionRepo *mock_notifier.MockSubscriptionRepository)
+	}{
+		{
+			name: "Subscription snoozed on the reminder day",
+			setupMock: func(mockSubscriptionRepo *mock_notifier.MockSubscriptionRepository) {
+				mockSubscriptionRepo.EXPECT().GetByUsers(2, 1).Return(&subscription.Subscription{ID: 1, UserID: 2, RelatedUserID: 1, SnoozedUntil: &snoozedUntil}, nil)
+			},
+		},
+		{
+			name: "Subscription removed after the first attempt",
+			setupMock: func(mockSubscriptionRepo *mock_notifier.MockSubscriptionRepository) {
+				mockSubscriptionRepo.EXPECT().GetByUsers(2, 1).Return(nil, subscription.ErrNotFound)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_notifier.NewMockUserRepository(ctrl)
+			mockSubscriptionRepo := mock_notifier.NewMockSubscriptionRepository(ctrl)
+			mockNotificationRepo := mock_notifier.NewMockNotificationRepository(ctrl)
+			mockNotificationRepo.EXPECT().ClaimDue(gomock.Any()).Return([]notification.Notification{record}, nil)
+			mockUserRepo.EXPECT().GetUserByID(2).Return(subscriber, nil)
+			mockUserRepo.EXPECT().GetUserByID(1).Return(celebrant, nil)
+			tt.setupMock(mockSubscriptionRepo)
+			mockNotificationRepo.EXPECT().MarkSkipped(7).Return(nil)
+
+			email := &memorySender{channel: "email"}
+			n := New(mockUserRepo, mockSubscriptionRepo, mockNotificationRepo, nil, NewRegistry(email), nil, DefaultConfig())
+			n.RetryFailedNotifications()
+
+			assert.Empty(t, email.sent)
+		})
+	}
+}
+
+func TestRetryDigestDropsSnoozedEntries(t *testing.T) {
+	// дайджест за 10 марта повторяется позже: пауза Олега к моменту повтора уже закончилась,
+	// но на день дайджеста она действовала
+	snoozedUntil := time.Date(2026, time.March, 12, 0, 0, 0, 0, time.UTC)
+	subscriber := user.User{ID: 2, Name: "Ivan", Email: "ivan@example.com", TimeZone: "UTC", DeliveryMode: user.DeliveryDigest}
+	anna := user.User{ID: 1, Name: "Anna", DateOfBirth: time.Date(1990, time.March, 11, 0, 0, 0, 0, time.UTC)}
+	record := notification.Notification{
+		ID:           7,
+		SubscriberID: subscriber.ID,
+		Kind:         notification.KindDigest,
+		DedupeKey:    "digest:2026-03-10:0123456789abcdef",
+		Channel:      "email",
+		Payload: notification.Payload{
+			Body:      "- Oleg - сегодня\n- Anna - завтра",
+			Reminders: []string{"reminder:1:2026:1", "reminder:3:2026:0"},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_notifier.NewMockUserRepository(ctrl)
+	mockSubscriptionRepo := mock_notifier.NewMockSubscriptionRepository(ctrl)
+	mockNotificationRepo := mock_notifier.NewMockNotificationRepository(ctrl)
+	mockNotificationRepo.EXPECT().ClaimDue(gomock.Any()).Return([]notification.Notification{record}, nil)
+	mockUserRepo.EXPECT().GetUserByID(2).Return(&subscriber, nil)
+	// подписки, добавленные после первой попытки, не запрашиваются
+	mockSubscriptionRepo.EXPECT().GetByUsers(2, 1).Return(&subscription.Subscription{ID: 1, UserID: 2, RelatedUserID: 1}, nil)
+	mockSubscriptionRepo.EXPECT().GetByUsers(2, 3).Return(&subscription.Subscription{ID: 2, UserID: 2, RelatedUserID: 3, SnoozedUntil: &snoozedUntil}, nil)
+	mockUserRepo.EXPECT().GetUserByID(1).Return(&anna, nil)
+	mockNotificationRepo.EXPECT().MarkSent(7).Return(nil)
+
+	email := &memorySender{channel: "email"}
+	n := New(mockUserRepo, mockSubscriptionRepo, mockNotificationRepo, nil, NewRegistry(email), nil, DefaultConfig())
+	n.RetryFailedNotifications()
+
+	assert.Len(t, email.sent, 1)
+	assert.Contains(t, email.sent[0].msg.Body, "- Anna - завтра")
+	assert.NotContains(t, email.sent[0].msg.Body, "Oleg")
+}
+
+func TestRetryDigestSkippedWhenAllEntriesRemoved(t *testing.T) {
+	subscriber := user.User{ID: 2, Name: "Ivan", Email: "ivan@example.com", TimeZone: "UTC", DeliveryMode: user.DeliveryDigest}
+	record := notification.Notification{
+		ID:           7,
+		SubscriberID: subscriber.ID,
+		Kind:         notification.KindDigest,
+		DedupeKey:    "digest:2026-03-10:0123456789abcdef",
+		Channel:      "email",
+		Payload:      notification.Payload{Body: "- Anna - завтра", Reminders: []string{"reminder:1:2026:1"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_notifier.NewMockUserRepository(ctrl)
+	mockSubscriptionRepo := mock_notifier.NewMockSubscriptionRepository(ctrl)
+	mockNotificationRepo := mock_notifier.NewMockNotificationRepository(ctrl)
+	mockNotificationRepo.EXPECT().ClaimDue(gomock.Any()).Return([]notification.Notification{record}, nil)
+	mockUserRepo.EXPECT().GetUserByID(2).Return(&subscriber, nil)
+	mockSubscriptionRepo.EXPECT().GetByUsers(2, 1).Return(nil, subscription.ErrNotFound)
+	mockNotificationRepo.EXPECT().MarkSkipped(7).Return(nil)
+
+	email := &memorySender{channel: "email"}
+	n := New(mockUserRepo, mockSubscriptionRepo, mockNotificationRepo, nil, NewRegistry(email), nil, DefaultConfig())
+	n.RetryFailedNotifications()
+
+	assert.Empty(t, email.sent)
+}
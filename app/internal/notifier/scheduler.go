@@ -0,0 +1,115 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/go-co-op/gocron"
+	"log"
+	"sync"
+	"time"
+)
+
+// StartBirthdayNotifier запускает по расписанию цикл рассылки, сводки и обработку очереди повторов.
+func (n *Notifier) StartBirthdayNotifier() error {
+	log.Println("Initializing the scheduler")
+
+	loc, err := time.LoadLocation(n.config.TimeZone)
+	if err != nil {
+		return fmt.Errorf("error loading location: %v", err)
+	}
+
+	s := gocron.NewScheduler(loc)
+	s.SingletonModeAll()
+
+	if _, err := s.Cron(n.config.Schedule).Do(n.runBirthdayCycle); err != nil {
+		return fmt.Errorf("invalid notifier schedule %q: %v", n.config.Schedule, err)
+	}
+
+	if _, err := s.Cron(n.config.SummarySchedule).Do(n.runSummaryCycle); err != nil {
+		return fmt.Errorf("invalid summary schedule %q: %v", n.config.SummarySchedule, err)
+	}
+
+	if _, err := s.Every(n.config.RetryInterval).Do(n.runRetryCycle); err != nil {
+		return fmt.Errorf("invalid retry interval %s: %v", n.config.RetryInterval, err)
+	}
+
+	n.scheduler = s
+	s.StartAsync()
+	log.Printf("Birthday notifier scheduled with %q (%s)\n", n.config.Schedule, n.config.TimeZone)
+
+	if n.config.RunOnStartup {
+		go n.runBirthdayCycle()
+		go n.runSummaryCycle()
+	}
+
+	return nil
+}
+
+// Stop останавливает планировщик и дожидается завершения запущенных циклов.
+func (n *Notifier) Stop() {
+	n.stateMu.Lock()
+	n.stopped = true
+	n.stateMu.Unlock()
+
+	if n.scheduler != nil {
+		n.scheduler.Stop()
+	}
+	n.running.Wait()
+	log.Println("Birthday notifier stopped")
+}
+
+// Ключи advisory-блокировок циклов, общие для всех экземпляров сервиса.
+const (
+	birthdayCycleLockKey int64 = 7304001
+	retryCycleLockKey    int64 = 7304002
+	summaryCycleLockKey  int64 = 7304003
+)
+
+func (n *Notifier) runBirthdayCycle() {
+	n.runExclusive(&n.cycleMu, birthdayCycleLockKey, "birthday notification", func() {
+		n.SendBirthdayNotifications()
+		n.SendGreetings()
+		n.SendAnnouncements()
+	})
+}
+
+func (n *Notifier) runRetryCycle() {
+	n.runExclusive(&n.retryMu, retryCycleLockKey, "retry", n.RetryFailedNotifications)
+}
+
+func (n *Notifier) runSummaryCycle() {
+	n.runExclusive(&n.summaryMu, summaryCycleLockKey, "summary", n.SendSummaries)
+}
+
+// runExclusive выполняет цикл, если предыдущий такой же ещё не завершился, нотификатор
+// не остановлен и ни один другой экземпляр сервиса не выполняет этот цикл сейчас.
+func (n *Notifier) runExclusive(mu *sync.Mutex, lockKey int64, name string, cycle func()) {
+	if !mu.TryLock() {
+		log.Printf("Previous %s cycle is still running, skipping\n", name)
+		return
+	}
+	defer mu.Unlock()
+
+	n.stateMu.Lock()
+	if n.stopped {
+		n.stateMu.Unlock()
+		return
+	}
+	n.running.Add(1)
+	n.stateMu.Unlock()
+	defer n.running.Done()
+
+	if n.locker != nil {
+		unlock, acquired, err := n.locker.TryLock(lockKey)
+		if err != nil {
+			log.Printf("Error acquiring %s cycle lock: %v\n", name, err)
+			return
+		}
+		if !acquired {
+			log.Printf("The %s cycle is running on another instance, skipping\n", name)
+			return
+		}
+		defer unlock()
+	}
+
+	cycle()
+}
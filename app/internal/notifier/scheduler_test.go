@@ -0,0 +1,161 @@
+package notifier
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		config, err := ConfigFromEnv()
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultConfig(), config)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("NOTIFIER_SCHEDULE", "15 12 * * *")
+		t.Setenv("NOTIFIER_TIME_ZONE", "Europe/Moscow")
+		t.Setenv("NOTIFIER_RUN_ON_STARTUP", "false")
+		t.Setenv("NOTIFIER_RETRY_INTERVAL", "30s")
+		t.Setenv("NOTIFIER_SUMMARY_SCHEDULE", "0 9 * * *")
+
+		config, err := ConfigFromEnv()
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Schedule: "15 12 * * *", TimeZone: "Europe/Moscow", RunOnStartup: false, RetryInterval: 30 * time.Second, SummarySchedule: "0 9 * * *"}, config)
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		t.Setenv("NOTIFIER_TIME_ZONE", "Mars/Olympus")
+		_, err := ConfigFromEnv()
+		assert.Error(t, err)
+	})
+}
+
+func TestMailConfigFromEnv(t *testing.T) {
+	t.Run("SMTP requires a sender address", func(t *testing.T) {
+		t.Setenv("SMTP_USERNAME", "")
+		_, err := MailConfigFromEnv()
+		assert.Error(t, err)
+	})
+
+	t.Run("Implicit TLS defaults to port 465", func(t *testing.T) {
+		t.Setenv("SMTP_USERNAME", "robot@example.com")
+		t.Setenv("SMTP_TLS", "tls")
+		t.Setenv("SMTP_AUTH", "login")
+
+		config, err := MailConfigFromEnv()
+		assert.NoError(t, err)
+		assert.Equal(t, "465", config.Port)
+		assert.Equal(t, "robot@example.com", config.From)
+		assert.Equal(t, SMTPAuthLogin, config.AuthMode)
+	})
+
+	t.Run("Maildir transport without credentials", func(t *testing.T) {
+		t.Setenv("MAIL_TRANSPORT", "maildir")
+		t.Setenv("MAIL_DIR", "/tmp/mail")
+
+		config, err := MailConfigFromEnv()
+		assert.NoError(t, err)
+		assert.Equal(t, MailTransportMaildir, config.Transport)
+		assert.Equal(t, "/tmp/mail", config.Dir)
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		t.Setenv("SMTP_USERNAME", "robot@example.com")
+		for key, value := range map[string]string{"MAIL_TRANSPORT": "pigeon", "SMTP_TLS": "ssl3", "SMTP_AUTH": "oauth", "SMTP_PORT": "abc"} {
+			t.Run(key, func(t *testing.T) {
+				t.Setenv(key, value)
+				_, err := MailConfigFromEnv()
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestStartBirthdayNotifierInvalidSchedule(t *testing.T) {
+	config := DefaultConfig()
+	config.Schedule = "not a cron"
+
+	n := New(nil, nil, nil, nil, NewRegistry(), nil, config)
+	assert.Error(t, n.StartBirthdayNotifier())
+}
+
+func TestRunExclusive(t *testing.T) {
+	n := New(nil, nil, nil, nil, NewRegistry(), nil, DefaultConfig())
+
+	var calls int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go n.runExclusive(&n.cycleMu, 1, "test", func() {
+		atomic.AddInt32(&calls, 1)
+		close(started)
+		<-release
+	})
+	<-started
+
+	// Пока первый цикл выполняется, второй пропускается
+	n.runExclusive(&n.cycleMu, 1, "test", func() { atomic.AddInt32(&calls, 1) })
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	stopped := make(chan struct{})
+	go func() {
+		n.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned before the running cycle finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-stopped
+
+	// После остановки циклы не запускаются
+	n.runExclusive(&n.cycleMu, 1, "test", func() { atomic.AddInt32(&calls, 1) })
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+type fakeLocker struct {
+	acquired bool
+	err      error
+	keys     []int64
+	unlocked int
+}
+
+func (l *fakeLocker) TryLock(key int64) (func(), bool, error) {
+	l.keys = append(l.keys, key)
+	if l.err != nil || !l.acquired {
+		return nil, false, l.err
+	}
+	return func() { l.unlocked++ }, true, nil
+}
+
+func TestRunExclusiveWithLocker(t *testing.T) {
+	testCases := []struct {
+		name             string
+		locker           *fakeLocker
+		expectedRuns     int
+		expectedUnlocked int
+	}{
+		{name: "Lock acquired", locker: &fakeLocker{acquired: true}, expectedRuns: 1, expectedUnlocked: 1},
+		{name: "Lock held by another instance", locker: &fakeLocker{acquired: false}, expectedRuns: 0, expectedUnlocked: 0},
+		{name: "Lock error", locker: &fakeLocker{err: assert.AnError}, expectedRuns: 0, expectedUnlocked: 0},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			n := New(nil, nil, nil, nil, NewRegistry(), tt.locker, DefaultConfig())
+
+			runs := 0
+			n.runExclusive(&n.cycleMu, birthdayCycleLockKey, "test", func() { runs++ })
+
+			assert.Equal(t, tt.expectedRuns, runs)
+			assert.Equal(t, tt.expectedUnlocked, tt.locker.unlocked)
+			assert.Equal(t, []int64{birthdayCycleLockKey}, tt.locker.keys)
+		})
+	}
+}
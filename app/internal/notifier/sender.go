@@ -0,0 +1,83 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/repository/user"
+	"sync"
+)
+
+// Message - готовое к отправке уведомление, не зависящее от канала доставки.
+type Message struct {
+	Subject string
+	// Body - текстовая версия, HTMLBody - необязательная HTML-версия для каналов, которые её поддерживают.
+	Body      string
+	HTMLBody  string
+	Celebrant user.User
+	// Kind - вид уведомления (reminder, digest, ...), EventID - идентификатор события,
+	// одинаковый при повторных отправках, чтобы получатель мог отбросить дубли.
+	Kind    string
+	EventID string
+}
+
+// Sender доставляет сообщение получателю по одному каналу (email, telegram, ...).
+type Sender interface {
+	// Channel возвращает уникальное имя канала.
+	Channel() string
+	// Accepts сообщает, может ли канал доставить сообщение этому получателю.
+	Accepts(recipient user.User) bool
+	Send(recipient user.User, msg Message) error
+}
+
+// Channels возвращает имена всех каналов, которые может включить или отключить пользователь,
+// независимо от того, подключены ли они в этом экземпляре.
+func Channels() []string {
+	return []string{ChannelEmail, ChannelTelegram, ChannelPush, ChannelInApp, ChannelWebhook}
+}
+
+// Registry хранит подключенные каналы доставки.
+type Registry struct {
+	mu      sync.RWMutex
+	senders []Sender
+}
+
+func NewRegistry(senders ...Sender) *Registry {
+	r := &Registry{}
+	for _, s := range senders {
+		r.Register(s)
+	}
+	return r
+}
+
+// Register добавляет канал; канал с тем же именем заменяется.
+func (r *Registry) Register(s Sender) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.senders {
+		if existing.Channel() == s.Channel() {
+			r.senders[i] = s
+			return
+		}
+	}
+	r.senders = append(r.senders, s)
+}
+
+func (r *Registry) Get(channel string) (Sender, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, s := range r.senders {
+		if s.Channel() == channel {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+func (r *Registry) Senders() []Sender {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	senders := make([]Sender, len(r.senders))
+	copy(senders, r.senders)
+	return senders
+}
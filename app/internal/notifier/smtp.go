@@ -0,0 +1,131 @@
+package notifier
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+)
+
+// SMTPTransport отправляет письма через SMTP-сервер с настраиваемыми TLS и авторизацией.
+type SMTPTransport struct {
+	config MailConfig
+	// tlsConfig переопределяется в тестах, чтобы доверять самоподписанному сертификату.
+	tlsConfig *tls.Config
+}
+
+func NewSMTPTransport(config MailConfig) *SMTPTransport {
+	return &SMTPTransport{
+		config:    config,
+		tlsConfig: &tls.Config{ServerName: config.Host},
+	}
+}
+
+func (t *SMTPTransport) Deliver(from string, to []string, message []byte) error {
+	client, err := t.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if t.config.TLSMode == SMTPTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(t.tlsConfig); err != nil {
+			return fmt.Errorf("starttls: %v", err)
+		}
+	}
+
+	if auth := t.auth(); auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth: %v", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (t *SMTPTransport) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(t.config.Host, t.config.Port)
+	dialer := &net.Dialer{Timeout: t.config.Timeout}
+
+	var conn net.Conn
+	var err error
+	if t.config.TLSMode == SMTPTLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, t.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %v", addr, err)
+	}
+
+	client, err := smtp.NewClient(conn, t.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func (t *SMTPTransport) auth() smtp.Auth {
+	switch t.config.AuthMode {
+	case SMTPAuthPlain:
+		return smtp.PlainAuth("", t.config.Username, t.config.Password, t.config.Host)
+	case SMTPAuthLogin:
+		return &loginAuth{username: t.config.Username, password: t.config.Password}
+	case SMTPAuthCRAMMD5:
+		return smtp.CRAMMD5Auth(t.config.Username, t.config.Password)
+	default:
+		return nil
+	}
+}
+
+// loginAuth реализует AUTH LOGIN, которого нет в net/smtp, но который требуют некоторые серверы.
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch string(fromServer) {
+	case "Username:":
+		return []byte(a.username), nil
+	case "Password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge: %q", fromServer)
+	}
+}
@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"bufio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+)
+
+// fakeSMTPServer принимает одно письмо и возвращает записанные команды и данные.
+func fakeSMTPServer(t *testing.T, extensions []string) (host, port string, received chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received = make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		reader := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				received <- lines
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				for _, ext := range extensions {
+					reply("250-" + ext)
+				}
+				reply("250 HELP")
+			case line == "DATA":
+				reply("354 go ahead")
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						received <- lines
+						return
+					}
+					dataLine = strings.TrimRight(dataLine, "\r\n")
+					if dataLine == "." {
+						break
+					}
+					lines = append(lines, dataLine)
+				}
+				reply("250 queued")
+			case line == "QUIT":
+				reply("221 bye")
+				received <- lines
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, err = net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return host, port, received
+}
+
+func TestSMTPTransport(t *testing.T) {
+	t.Run("Plain connection without auth", func(t *testing.T) {
+		host, port, received := fakeSMTPServer(t, nil)
+		config := DefaultMailConfig()
+		config.Host, config.Port = host, port
+		config.TLSMode, config.AuthMode = SMTPTLSNone, SMTPAuthNone
+
+		err := NewSMTPTransport(config).Deliver("from@example.com", []string{"to@example.com"}, []byte("Subject: Hi\r\n\r\nHello"))
+		require.NoError(t, err)
+
+		lines := <-received
+		assert.Contains(t, lines, "MAIL FROM:<from@example.com>")
+		assert.Contains(t, lines, "RCPT TO:<to@example.com>")
+		assert.Contains(t, lines, "Hello")
+	})
+
+	t.Run("STARTTLS required but not offered", func(t *testing.T) {
+		host, port, _ := fakeSMTPServer(t, nil)
+		config := DefaultMailConfig()
+		config.Host, config.Port = host, port
+
+		err := NewSMTPTransport(config).Deliver("from@example.com", []string{"to@example.com"}, []byte("Hello"))
+		assert.EqualError(t, err, "smtp server does not support STARTTLS")
+	})
+}
+
+func TestLoginAuth(t *testing.T) {
+	auth := &loginAuth{username: "user", password: "secret"}
+
+	_, _, err := auth.Start(&smtp.ServerInfo{Name: "mail", TLS: false})
+	assert.Error(t, err)
+
+	mechanism, _, err := auth.Start(&smtp.ServerInfo{Name: "mail", TLS: true})
+	require.NoError(t, err)
+	assert.Equal(t, "LOGIN", mechanism)
+
+	answer, err := auth.Next([]byte("Username:"), true)
+	require.NoError(t, err)
+	assert.Equal(t, "user", string(answer))
+
+	answer, err = auth.Next([]byte("Password:"), true)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(answer))
+}
@@ -0,0 +1,144 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/birthday"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/user"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// summary - сводка дней рождения за период с from по to включительно.
+type summary struct {
+	kind   string
+	period string
+	from   time.Time
+	to     time.Time
+}
+
+// SendSummaries рассылает подписанным пользователям сводки: по понедельникам - дни рождения
+// на ближайшую неделю, первого числа - на весь месяц. Как и напоминания, сводки уходят
+// в выбранный пользователем час по его местному времени, повторный запуск их не дублирует.
+func (n *Notifier) SendSummaries() {
+	n.sendSummaries(time.Now())
+}
+
+func (n *Notifier) sendSummaries(now time.Time) {
+	recipients, err := n.userRepo.GetSummaryRecipients()
+	if err != nil {
+		log.Println("Error fetching summary recipients:", err)
+		return
+	}
+
+	// у пользователей из разных часовых поясов периоды могут не совпадать
+	celebrantsByPeriod := make(map[string][]user.User)
+
+	for _, recipient := range recipients {
+		for _, s := range dueSummaries(recipient, now) {
+			key := s.from.Format("2006-01-02") + "/" + s.to.Format("2006-01-02")
+			celebrants, ok := celebrantsByPeriod[key]
+			if !ok {
+				celebrants, err = n.userRepo.GetUsersWithBirthdayBetween(s.from, s.to)
+				if err != nil {
+					log.Printf("Error fetching birthdays from %s to %s: %v\n", s.from.Format("2006-01-02"), s.to.Format("2006-01-02"), err)
+					continue
+				}
+				celebrantsByPeriod[key] = celebrants
+			}
+
+			data := summaryData(recipient, s, celebrants)
+			if len(data.Birthdays) == 0 {
+				continue
+			}
+
+			msg, err := n.templates.Render(KindSummary, recipient.Locale, data)
+			if err != nil {
+				log.Printf("Error rendering %s for user ID %d: %v\n", s.kind, recipient.ID, err)
+				continue
+			}
+			n.deliver(recipient, summaryRecord(recipient, s), msg)
+		}
+	}
+}
+
+// dueSummaries возвращает сводки, которые пора отправить пользователю к моменту now.
+func dueSummaries(u user.User, now time.Time) []summary {
+	local := now.In(loadLocation(u.TimeZone))
+	if local.Hour() < u.NotifyHour || u.InQuietHours(local.Hour()) {
+		return nil
+	}
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+
+	var summaries []summary
+	if u.WeeklySummary && today.Weekday() == time.Monday {
+		summaries = append(summaries, summary{
+			kind:   notification.KindWeeklySummary,
+			period: PeriodWeek,
+			from:   today,
+			to:     today.AddDate(0, 0, 6),
+		})
+	}
+	if u.MonthlySummary && today.Day() == 1 {
+		summaries = append(summaries, summary{
+			kind:   notification.KindMonthlySummary,
+			period: PeriodMonth,
+			from:   today,
+			to:     today.AddDate(0, 1, -1),
+		})
+	}
+	return summaries
+}
+
+// summaryData перечисляет дни рождения периода по порядку, не включая самого получателя.
+func summaryData(recipient user.User, s summary, celebrants []user.User) TemplateData {
+	data := TemplateData{
+		SubscriberName: recipient.Name,
+		Period:         s.period,
+		Date:           s.from,
+		Until:          s.to,
+	}
+	for _, celebrant := range celebrants {
+		if celebrant.ID == recipient.ID {
+			continue
+		}
+		occurrence := birthday.Next(celebrant.DateOfBirth, s.from, birthday.Policy(celebrant.LeapPolicy))
+		if occurrence.After(s.to) {
+			continue
+		}
+		data.Birthdays = append(data.Birthdays, TemplateData{
+			CelebrantName: celebrant.Name,
+			Age:           birthday.Age(celebrant.DateOfBirth, occurrence),
+			DaysUntil:     daysBetween(s.from, occurrence),
+			Date:          occurrence,
+		})
+	}
+
+	sort.SliceStable(data.Birthdays, func(i, j int) bool {
+		if !data.Birthdays[i].Date.Equal(data.Birthdays[j].Date) {
+			return data.Birthdays[i].Date.Before(data.Birthdays[j].Date)
+		}
+		return data.Birthdays[i].CelebrantName < data.Birthdays[j].CelebrantName
+	})
+	return data
+}
+
+func summaryRecord(recipient user.User, s summary) notification.Notification {
+	return notification.Notification{
+		SubscriberID: recipient.ID,
+		BirthdayYear: s.from.Year(),
+		Kind:         s.kind,
+		DedupeKey:    s.kind + ":" + s.from.Format("2006-01-02"),
+	}
+}
+
+// daysBetween считает календарные дни между полуночами from и to с учетом перехода на летнее время.
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
@@ -0,0 +1,158 @@
+package notifier
+
+import (
+	mock_notifier "birthdayReminder/internal/notifier/mocks"
+	"birthdayReminder/internal/repository/notification"
+	"birthdayReminder/internal/repository/user"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDueSummaries(t *testing.T) {
+	recipient := user.User{ID: 1, TimeZone: "UTC", NotifyHour: 9, WeeklySummary: true, MonthlySummary: true}
+
+	testCases := []struct {
+		name     string
+		user     user.User
+		now      time.Time
+		expected []string
+	}{
+		{
+			name:     "Monday",
+			user:     recipient,
+			now:      time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC),
+			expected: []string{"weekly_summary 2026-10-19 2026-10-25"},
+		},
+		{
+			name:     "First of the month falling on Monday",
+			user:     recipient,
+			now:      time.Date(2027, time.February, 1, 10, 0, 0, 0, time.UTC),
+			expected: []string{"weekly_summary 2027-02-01 2027-02-07", "monthly_summary 2027-02-01 2027-02-28"},
+		},
+		{
+			name:     "Week across year end",
+			user:     recipient,
+			now:      time.Date(2026, time.December, 28, 10, 0, 0, 0, time.UTC),
+			expected: []string{"weekly_summary 2026-12-28 2027-01-03"},
+		},
+		{
+			name:     "Preferred hour not reached yet",
+			user:     recipient,
+			now:      time.Date(2026, time.October, 19, 8, 0, 0, 0, time.UTC),
+			expected: nil,
+		},
+		{
+			name:     "Not opted in",
+			user:     user.User{ID: 1, TimeZone: "UTC"},
+			now:      time.Date(2027, time.February, 1, 10, 0, 0, 0, time.UTC),
+			expected: nil,
+		},
+		{
+			name:     "Already Monday in subscriber's time zone",
+			user:     user.User{ID: 1, TimeZone: "Pacific/Kiritimati", WeeklySummary: true},
+			now:      time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC),
+			expected: []string{"weekly_summary 2026-10-19 2026-10-25"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var summaries []string
+			for _, s := range dueSummaries(tt.user, tt.now) {
+				summaries = append(summaries, s.kind+" "+s.from.Format("2006-01-02")+" "+s.to.Format("2006-01-02"))
+			}
+			assert.Equal(t, tt.expected, summaries)
+		})
+	}
+}
+
+func TestSummaryData(t *testing.T) {
+	s := summary{
+		kind:   notification.KindWeeklySummary,
+		period: PeriodWeek,
+		from:   time.Date(2026, time.December, 28, 0, 0, 0, 0, time.UTC),
+		to:     time.Date(2027, time.January, 3, 0, 0, 0, 0, time.UTC),
+	}
+	recipient := user.User{ID: 1, Name: "Ivan", DateOfBirth: time.Date(1990, time.December, 30, 0, 0, 0, 0, time.UTC)}
+	celebrants := []user.User{
+		recipient,
+		{ID: 2, Name: "Olga", DateOfBirth: time.Date(1991, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: 3, Name: "Anna", DateOfBirth: time.Date(1985, time.December, 29, 0, 0, 0, 0, time.UTC)},
+		{ID: 4, Name: "Petr", DateOfBirth: time.Date(1985, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	data := summaryData(recipient, s, celebrants)
+
+	assert.Equal(t, PeriodWeek, data.Period)
+	if assert.Len(t, data.Birthdays, 2) {
+		assert.Equal(t, "Anna", data.Birthdays[0].CelebrantName)
+		assert.Equal(t, 41, data.Birthdays[0].Age)
+		assert.Equal(t, 1, data.Birthdays[0].DaysUntil)
+		assert.Equal(t, "Olga", data.Birthdays[1].CelebrantName)
+		assert.Equal(t, 36, data.Birthdays[1].Age)
+		assert.Equal(t, 5, data.Birthdays[1].DaysUntil)
+	}
+}
+
+func TestSendSummaries(t *testing.T) {
+	monday := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	dob := time.Date(1996, time.October, 21, 0, 0, 0, 0, time.UTC)
+	recipient := user.User{ID: 1, Name: "Ivan", Email: "ivan@example.com", DateOfBirth: dob, TimeZone: "UTC", Locale: "en", WeeklySummary: true}
+	celebrant := user.User{ID: 2, Name: "Anna", DateOfBirth: dob}
+
+	testCases := []struct {
+		name         string
+		setupMock    func(mockUserRepo *mock_notifier.MockUserRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository)
+		expectedSent int
+	}{
+		{
+			name: "Sends weekly summary",
+			setupMock: func(mockUserRepo *mock_notifier.MockUserRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockUserRepo.EXPECT().GetSummaryRecipients().Return([]user.User{recipient}, nil)
+				mockUserRepo.EXPECT().GetUsersWithBirthdayBetween(
+					time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+					time.Date(2026, time.October, 25, 0, 0, 0, 0, time.UTC),
+				).Return([]user.User{recipient, celebrant}, nil)
+				mockNotificationRepo.EXPECT().Claim(gomock.Any()).DoAndReturn(func(record *notification.Notification) (bool, error) {
+					assert.Equal(t, "weekly_summary:2026-10-19", record.DedupeKey)
+					record.ID = 10
+					return true, nil
+				})
+				mockNotificationRepo.EXPECT().MarkSent(10).Return(nil)
+			},
+			expectedSent: 1,
+		},
+		{
+			name: "Skips empty summary",
+			setupMock: func(mockUserRepo *mock_notifier.MockUserRepository, mockNotificationRepo *mock_notifier.MockNotificationRepository) {
+				mockUserRepo.EXPECT().GetSummaryRecipients().Return([]user.User{recipient}, nil)
+				mockUserRepo.EXPECT().GetUsersWithBirthdayBetween(gomock.Any(), gomock.Any()).Return([]user.User{recipient}, nil)
+			},
+			expectedSent: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := mock_notifier.NewMockUserRepository(ctrl)
+			mockNotificationRepo := mock_notifier.NewMockNotificationRepository(ctrl)
+			tt.setupMock(mockUserRepo, mockNotificationRepo)
+
+			email := &memorySender{channel: "email"}
+			n := New(mockUserRepo, nil, mockNotificationRepo, nil, NewRegistry(email), nil, DefaultConfig())
+			n.sendSummaries(monday)
+
+			assert.Len(t, email.sent, tt.expectedSent)
+			for _, sent := range email.sent {
+				assert.Equal(t, "Birthdays this week (Oct 19 - Oct 25)", sent.msg.Subject)
+				assert.Contains(t, sent.msg.Body, "- Oct 21 - Anna, turning 30")
+				assert.NotContains(t, sent.msg.Body, "- Oct 21 - Ivan")
+			}
+		})
+	}
+}
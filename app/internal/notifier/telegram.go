@@ -0,0 +1,26 @@
+package notifier
+
+import "birthdayReminder/internal/repository/user"
+
+const ChannelTelegram = "telegram"
+
+// TelegramSender доставляет уведомления в привязанный пользователем Telegram-чат.
+type TelegramSender struct {
+	client TelegramClient
+}
+
+func NewTelegramSender(client TelegramClient) *TelegramSender {
+	return &TelegramSender{client: client}
+}
+
+func (s *TelegramSender) Channel() string {
+	return ChannelTelegram
+}
+
+func (s *TelegramSender) Accepts(recipient user.User) bool {
+	return recipient.TelegramChatID != 0
+}
+
+func (s *TelegramSender) Send(recipient user.User, msg Message) error {
+	return s.client.SendMessage(recipient.TelegramChatID, msg.Body)
+}
@@ -0,0 +1,24 @@
+package notifier
+
+import (
+	mock_notifier "birthdayReminder/internal/notifier/mocks"
+	"birthdayReminder/internal/repository/user"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTelegramSender(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mock_notifier.NewMockTelegramClient(ctrl)
+	client.EXPECT().SendMessage(int64(42), "Завтра день рождения у Anna!").Return(nil)
+
+	sender := NewTelegramSender(client)
+	assert.False(t, sender.Accepts(user.User{ID: 1, Email: "ivan@example.com"}))
+
+	recipient := user.User{ID: 1, TelegramChatID: 42}
+	assert.True(t, sender.Accepts(recipient))
+	assert.NoError(t, sender.Send(recipient, Message{Subject: "Напоминание", Body: "Завтра день рождения у Anna!"}))
+}
@@ -0,0 +1,178 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/repository/user"
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+const (
+	KindReminder = "reminder"
+	KindDigest   = "digest"
+	KindSummary  = "summary"
+	KindGreeting = "greeting"
+	// KindVerification - письмо со ссылкой подтверждения адреса, отправляется и на неподтвержденный адрес.
+	KindVerification = "verification"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// TemplateData - переменные, доступные в шаблонах уведомлений.
+type TemplateData struct {
+	SubscriberName string
+	CelebrantName  string
+	// Age - сколько исполняется имениннику.
+	Age int
+	// DaysUntil - сколько дней осталось до дня рождения (0 - сегодня).
+	DaysUntil int
+	Date      time.Time
+	// Birthdays - дни рождения, перечисленные в дайджесте или сводке.
+	Birthdays []TemplateData
+	// Period (week или month) и Until - период сводки, начинающийся в Date.
+	Period string
+	Until  time.Time
+	// Link - ссылка подтверждения email.
+	Link string
+}
+
+type localizedTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Templates хранит шаблоны уведомлений: templates/<locale>/<kind>.txt.tmpl с блоками
+// "subject" и "text" и необязательный templates/<locale>/<kind>.html.tmpl с блоком "html".
+type Templates struct {
+	templates map[string]localizedTemplate
+}
+
+// dateLayouts - формат даты без года для каждого языка шаблонов.
+var dateLayouts = map[string]string{
+	"ru": "02.01",
+	"en": "Jan 2",
+}
+
+// templateFuncs возвращает функции шаблонов для языка locale; для неизвестного языка даты форматируются как для языка по умолчанию.
+func templateFuncs(locale string) map[string]interface{} {
+	layout, ok := dateLayouts[locale]
+	if !ok {
+		layout = dateLayouts[user.DefaultLocale]
+	}
+	return map[string]interface{}{
+		"plural": pluralRu,
+		"date": func(t time.Time) string {
+			return t.Format(layout)
+		},
+	}
+}
+
+var defaultTemplates = mustLoadTemplates(templateFS)
+
+func mustLoadTemplates(fsys fs.FS) *Templates {
+	t, err := LoadTemplates(fsys)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func LoadTemplates(fsys fs.FS) (*Templates, error) {
+	t := &Templates{templates: make(map[string]localizedTemplate)}
+
+	textFiles, err := fs.Glob(fsys, "templates/*/*.txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range textFiles {
+		locale := path.Base(path.Dir(file))
+		kind := strings.TrimSuffix(path.Base(file), ".txt.tmpl")
+
+		text, err := texttemplate.New(path.Base(file)).Funcs(templateFuncs(locale)).ParseFS(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing template %s: %v", file, err)
+		}
+		lt := localizedTemplate{text: text}
+
+		htmlFile := path.Join(path.Dir(file), kind+".html.tmpl")
+		if _, err := fs.Stat(fsys, htmlFile); err == nil {
+			lt.html, err = htmltemplate.New(path.Base(htmlFile)).Funcs(templateFuncs(locale)).ParseFS(fsys, htmlFile)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing template %s: %v", htmlFile, err)
+			}
+		}
+
+		t.templates[locale+"/"+kind] = lt
+	}
+
+	return t, nil
+}
+
+// Render собирает сообщение вида kind на языке locale; неизвестный язык заменяется языком по умолчанию.
+func (t *Templates) Render(kind, locale string, data interface{}) (Message, error) {
+	lt, ok := t.templates[locale+"/"+kind]
+	if !ok {
+		lt, ok = t.templates[user.DefaultLocale+"/"+kind]
+		if !ok {
+			return Message{}, fmt.Errorf("template %q not found", kind)
+		}
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := lt.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("error rendering subject: %v", err)
+	}
+	if err := lt.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return Message{}, fmt.Errorf("error rendering text: %v", err)
+	}
+	if lt.html != nil {
+		if err := lt.html.ExecuteTemplate(&html, "html", data); err != nil {
+			return Message{}, fmt.Errorf("error rendering html: %v", err)
+		}
+	}
+
+	return Message{
+		Subject:  strings.TrimSpace(subject.String()),
+		Body:     strings.TrimSpace(text.String()),
+		HTMLBody: strings.TrimSpace(html.String()),
+	}, nil
+}
+
+// HasLocale сообщает, есть ли шаблоны для языка locale.
+func (t *Templates) HasLocale(locale string) bool {
+	for key := range t.templates {
+		if strings.HasPrefix(key, locale+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportedLocale проверяет, что для языка есть встроенные шаблоны.
+func SupportedLocale(locale string) bool {
+	return defaultTemplates.HasLocale(locale)
+}
+
+// pluralRu выбирает форму слова для числа n: plural 1 "день" "дня" "дней".
+func pluralRu(n int, one, few, many string) string {
+	n %= 100
+	if n >= 11 && n <= 14 {
+		return many
+	}
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	default:
+		return many
+	}
+}
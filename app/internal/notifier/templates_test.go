@@ -0,0 +1,102 @@
+package notifier
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRenderReminder(t *testing.T) {
+	date := time.Date(2026, time.October, 24, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		locale          string
+		data            TemplateData
+		expectedSubject string
+		expectedBody    string
+		expectedHTML    string
+	}{
+		{
+			name:            "Russian, tomorrow",
+			locale:          "ru",
+			data:            TemplateData{SubscriberName: "Иван", CelebrantName: "Анна", Age: 30, DaysUntil: 1, Date: date},
+			expectedSubject: "Завтра день рождения у Анна",
+			expectedBody:    "Завтра день рождения у Анна - исполняется 30!",
+			expectedHTML:    "<strong>Анна</strong>",
+		},
+		{
+			name:            "Russian, plural forms",
+			locale:          "ru",
+			data:            TemplateData{SubscriberName: "Иван", CelebrantName: "Анна", DaysUntil: 3, Date: date},
+			expectedSubject: "Через 3 дня день рождения у Анна",
+			expectedBody:    "Через 3 дня (24.10)",
+		},
+		{
+			name:            "English, week ahead",
+			locale:          "en",
+			data:            TemplateData{SubscriberName: "John", CelebrantName: "Anna", Age: 41, DaysUntil: 7, Date: date},
+			expectedSubject: "Anna's birthday is in 7 days",
+			expectedBody:    "they are turning 41",
+			expectedHTML:    "In 7 days (Oct 24)",
+		},
+		{
+			name:            "Unknown locale falls back to Russian",
+			locale:          "de",
+			data:            TemplateData{SubscriberName: "Hans", CelebrantName: "Anna", DaysUntil: 0, Date: date},
+			expectedSubject: "Сегодня день рождения у Anna",
+			expectedBody:    "Здравствуйте, Hans!",
+		},
+		{
+			name:            "HTML is escaped",
+			locale:          "en",
+			data:            TemplateData{SubscriberName: "John", CelebrantName: "<b>Anna</b>", DaysUntil: 1, Date: date},
+			expectedSubject: "Tomorrow is <b>Anna</b>'s birthday",
+			expectedHTML:    "&lt;b&gt;Anna&lt;/b&gt;",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := defaultTemplates.Render(KindReminder, tt.locale, tt.data)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedSubject, msg.Subject)
+			assert.Contains(t, msg.Body, tt.expectedBody)
+			assert.Contains(t, msg.HTMLBody, tt.expectedHTML)
+		})
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	data := TemplateData{
+		SubscriberName: "John",
+		Birthdays: []TemplateData{
+			{CelebrantName: "Anna", Age: 30, DaysUntil: 0, Date: time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)},
+			{CelebrantName: "Oleg", DaysUntil: 3, Date: time.Date(2026, time.October, 20, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	msg, err := defaultTemplates.Render(KindDigest, "en", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Birthdays: Anna, Oleg", msg.Subject)
+	assert.Contains(t, msg.Body, "- Anna - today (Oct 17), turning 30\n- Oleg - in 3 days (Oct 20)\n")
+	assert.Contains(t, msg.HTMLBody, "<li><strong>Oleg</strong> - in 3 days (Oct 20)</li>")
+
+	msg, err = defaultTemplates.Render(KindDigest, "ru", data)
+	require.NoError(t, err)
+	assert.Contains(t, msg.Body, "- Oleg - через 3 дня (20.10)")
+}
+
+func TestPluralRu(t *testing.T) {
+	for n, expected := range map[int]string{1: "день", 2: "дня", 5: "дней", 11: "дней", 14: "дней", 21: "день", 22: "дня", 111: "дней"} {
+		assert.Equal(t, expected, pluralRu(n, "день", "дня", "дней"), "n=%d", n)
+	}
+}
+
+func TestSupportedLocale(t *testing.T) {
+	assert.True(t, SupportedLocale("ru"))
+	assert.True(t, SupportedLocale("en"))
+	assert.False(t, SupportedLocale("de"))
+}
@@ -0,0 +1,197 @@
+package notifier
+
+import (
+	"birthdayReminder/internal/netguard"
+	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/repository/webhook"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ChannelWebhook = "webhook"
+
+	// Заголовки запроса вебхука. Подпись - HMAC-SHA256 от "<timestamp>.<тело запроса>" на секрете вебхука.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-ID"
+)
+
+// WebhookEvent - тело запроса, которое получает вебхук.
+type WebhookEvent struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Subscriber WebhookUser    `json:"subscriber"`
+	Celebrant  *WebhookUser   `json:"celebrant,omitempty"`
+	Message    WebhookMessage `json:"message"`
+}
+
+type WebhookUser struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
+}
+
+type WebhookMessage struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+}
+
+// WebhookSender рассылает события на вебхуки получателя и глобальные вебхуки администраторов.
+// Каждая попытка сохраняется в истории доставки; если хотя бы один вебхук не ответил 2xx,
+// отправка считается неудачной и повторяется общей очередью повторов только для недоставленных вебхуков.
+type WebhookSender struct {
+	repo       WebhookRepository
+	httpClient *http.Client
+}
+
+// NewWebhookSender создает отправителя, который не ходит во внутреннюю сеть; allowPrivate снимает
+// это ограничение для тестов с локальными заглушками.
+func NewWebhookSender(repo WebhookRepository, allowPrivate bool) *WebhookSender {
+	return &WebhookSender{
+		repo:       repo,
+		httpClient: netguard.NewHTTPClient(10*time.Second, allowPrivate),
+	}
+}
+
+func (s *WebhookSender) Channel() string {
+	return ChannelWebhook
+}
+
+func (s *WebhookSender) Accepts(recipient user.User) bool {
+	ok, err := s.repo.HasTargets(recipient.ID)
+	if err != nil {
+		// не теряем событие: ошибка повторится в Send и попадёт в очередь повторов
+		log.Printf("Error checking webhooks for user ID %d: %v\n", recipient.ID, err)
+		return true
+	}
+	return ok
+}
+
+func (s *WebhookSender) Send(recipient user.User, msg Message) error {
+	targets, err := s.repo.GetTargets(recipient.ID, msg.EventID)
+	if err != nil {
+		return fmt.Errorf("error fetching webhooks: %v", err)
+	}
+
+	body, err := json.Marshal(newWebhookEvent(recipient, msg))
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, target := range targets {
+		if err := s.post(target, msg, body); err != nil {
+			failed = append(failed, fmt.Sprintf("webhook %d: %v", target.ID, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("webhook delivery failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (s *WebhookSender) post(target webhook.Webhook, msg Message, body []byte) error {
+	delivery := webhook.Delivery{WebhookID: target.ID, EventID: msg.EventID, EventType: webhookEventType(msg.Kind)}
+
+	started := time.Now()
+	statusCode, err := s.do(target, delivery, body)
+	delivery.DurationMs = int(time.Since(started).Milliseconds())
+
+	if statusCode != 0 {
+		delivery.StatusCode = &statusCode
+	}
+	if err != nil {
+		// история доставки видна владельцу вебхука, поэтому текст ошибки подключения в нее не попадает:
+		// иначе по нему можно изучать сеть, из которой работает сервис
+		log.Printf("Error delivering to webhook ID %d: %v\n", target.ID, err)
+		errText := deliveryError(statusCode)
+		delivery.Error = &errText
+	} else {
+		delivery.Success = true
+	}
+
+	if recordErr := s.repo.RecordDelivery(&delivery); recordErr != nil {
+		log.Printf("Error recording delivery to webhook ID %d: %v\n", target.ID, recordErr)
+	}
+	return err
+}
+
+// deliveryError - текст ошибки для истории доставки: только код ответа или общее сообщение.
+func deliveryError(statusCode int) string {
+	if statusCode != 0 {
+		return fmt.Sprintf("unexpected status %d", statusCode)
+	}
+	return "request failed"
+}
+
+// do отправляет подписанный запрос и возвращает код ответа (0, если ответа не было).
+func (s *WebhookSender) do(target webhook.Webhook, delivery webhook.Delivery, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, delivery.EventID)
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(target.Secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook возвращает значение заголовка подписи: "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)).
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookEventType(kind string) string {
+	if kind == "" {
+		kind = KindReminder
+	}
+	return "birthday." + kind
+}
+
+func newWebhookEvent(recipient user.User, msg Message) WebhookEvent {
+	event := WebhookEvent{
+		ID:         msg.EventID,
+		Type:       webhookEventType(msg.Kind),
+		CreatedAt:  time.Now().UTC(),
+		Subscriber: WebhookUser{ID: recipient.ID, Name: recipient.Name},
+		Message:    WebhookMessage{Subject: msg.Subject, Text: msg.Body},
+	}
+	if msg.Celebrant.ID != 0 {
+		dob := msg.Celebrant.DateOfBirth
+		event.Celebrant = &WebhookUser{ID: msg.Celebrant.ID, Name: msg.Celebrant.Name, DateOfBirth: &dob}
+	}
+	return event
+}
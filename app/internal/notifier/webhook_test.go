@@ -0,0 +1,129 @@
+package notifier
+
+import (
+	mock_notifier "birthdayReminder/internal/notifier/mocks"
+	"birthdayReminder/internal/repository/user"
+	"birthdayReminder/internal/repository/webhook"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	// эталон: echo -n '1700000000.{"id":"1"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"sha256=086f6aff7bd084c98679825129c5a64dbad88c760016d6d2c0fb123f27951d54",
+		SignWebhook("secret", "1700000000", []byte(`{"id":"1"}`)),
+	)
+}
+
+func TestWebhookSender(t *testing.T) {
+	var received []*http.Request
+	var bodies [][]byte
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r)
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ok.Close()
+
+	recipient := user.User{ID: 2, Name: "Ivan"}
+	msg := Message{
+		Subject:   "Tomorrow is Anna's birthday",
+		Body:      "Hi Ivan",
+		Celebrant: user.User{ID: 1, Name: "Anna", DateOfBirth: time.Date(1990, time.October, 18, 0, 0, 0, 0, time.UTC)},
+		Kind:      KindReminder,
+		EventID:   "2:reminder:1:2026:1",
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_notifier.NewMockWebhookRepository(ctrl)
+	repo.EXPECT().GetTargets(2, "2:reminder:1:2026:1").Return([]webhook.Webhook{
+		{ID: 1, URL: ok.URL, Secret: "user-secret"},
+		{ID: 2, URL: failing.URL, Secret: "admin-secret", IsGlobal: true},
+	}, nil)
+
+	var deliveries []webhook.Delivery
+	repo.EXPECT().RecordDelivery(gomock.Any()).Times(2).DoAndReturn(func(d *webhook.Delivery) error {
+		deliveries = append(deliveries, *d)
+		return nil
+	})
+
+	err := NewWebhookSender(repo, true).Send(recipient, msg)
+	assert.EqualError(t, err, "webhook delivery failed: webhook 2: unexpected status 502")
+
+	require.Len(t, received, 1)
+	req := received[0]
+	assert.Equal(t, "birthday.reminder", req.Header.Get(WebhookEventHeader))
+	assert.Equal(t, "2:reminder:1:2026:1", req.Header.Get(WebhookIDHeader))
+	assert.Equal(t, SignWebhook("user-secret", req.Header.Get(WebhookTimestampHeader), bodies[0]), req.Header.Get(WebhookSignatureHeader))
+
+	var event WebhookEvent
+	require.NoError(t, json.Unmarshal(bodies[0], &event))
+	assert.Equal(t, "birthday.reminder", event.Type)
+	assert.Equal(t, 2, event.Subscriber.ID)
+	if assert.NotNil(t, event.Celebrant) {
+		assert.Equal(t, "Anna", event.Celebrant.Name)
+	}
+	assert.Equal(t, "Hi Ivan", event.Message.Text)
+
+	require.Len(t, deliveries, 2)
+	assert.True(t, deliveries[0].Success)
+	assert.Equal(t, http.StatusNoContent, *deliveries[0].StatusCode)
+	assert.False(t, deliveries[1].Success)
+	assert.Equal(t, http.StatusBadGateway, *deliveries[1].StatusCode)
+	assert.Equal(t, "unexpected status 502", *deliveries[1].Error)
+}
+
+func TestWebhookSenderAccepts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_notifier.NewMockWebhookRepository(ctrl)
+	repo.EXPECT().HasTargets(1).Return(false, nil)
+	repo.EXPECT().HasTargets(2).Return(true, nil)
+
+	sender := NewWebhookSender(repo, true)
+	assert.False(t, sender.Accepts(user.User{ID: 1}))
+	assert.True(t, sender.Accepts(user.User{ID: 2}))
+}
+
+func TestWebhookSenderRefusesInternalTargets(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached an internal address")
+	}))
+	defer internal.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_notifier.NewMockWebhookRepository(ctrl)
+	repo.EXPECT().GetTargets(2, "2:reminder:1:2026:1").Return([]webhook.Webhook{{ID: 1, URL: internal.URL, Secret: "secret"}}, nil)
+
+	var delivery webhook.Delivery
+	repo.EXPECT().RecordDelivery(gomock.Any()).DoAndReturn(func(d *webhook.Delivery) error {
+		delivery = *d
+		return nil
+	})
+
+	err := NewWebhookSender(repo, false).Send(user.User{ID: 2}, Message{Body: "Hi", EventID: "2:reminder:1:2026:1"})
+	assert.Error(t, err)
+
+	assert.False(t, delivery.Success)
+	assert.Nil(t, delivery.StatusCode)
+	// адрес и причина отказа в историю доставки не попадают
+	assert.Equal(t, "request failed", *delivery.Error)
+}
@@ -0,0 +1,20 @@
+package announcement
+
+import "time"
+
+// Channel - командный чат (Slack, Mattermost), куда публикуются объявления о днях рождения
+// через incoming webhook.
+type Channel struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Format - шаблон text/template сообщения, см. notifier.AnnouncementData.
+	Format string `json:"format"`
+	// DaysAhead - за сколько дней объявлять (0 - в сам день рождения).
+	DaysAhead int `json:"days_ahead"`
+	// TimeZone и PostHour - когда по местному времени команды публиковать объявление.
+	TimeZone  string    `json:"time_zone"`
+	PostHour  int       `json:"post_hour"`
+	CreatedBy int       `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,85 @@
+package announcement
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrNotFound = errors.New("announcement channel not found")
+
+const columns = `id, name, url, format, days_ahead, time_zone, post_hour, created_by, created_at`
+
+type Repo struct {
+	db DBPool
+}
+
+func NewRepo(db DBPool) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) Create(c *Channel) error {
+	query := `
+		INSERT INTO announcement_channels (name, url, format, days_ahead, time_zone, post_hour, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(context.Background(), query, c.Name, c.URL, c.Format, c.DaysAhead, c.TimeZone, c.PostHour, c.CreatedBy).
+		Scan(&c.ID, &c.CreatedAt)
+}
+
+func (r *Repo) GetAll() ([]Channel, error) {
+	rows, err := r.db.Query(context.Background(), `SELECT `+columns+` FROM announcement_channels ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channels []Channel
+	for rows.Next() {
+		var c Channel
+		if err := rows.Scan(&c.ID, &c.Name, &c.URL, &c.Format, &c.DaysAhead, &c.TimeZone, &c.PostHour, &c.CreatedBy, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		channels = append(channels, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return channels, nil
+}
+
+func (r *Repo) Delete(id int) error {
+	tag, err := r.db.Exec(context.Background(), `DELETE FROM announcement_channels WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Claim резервирует объявление о дне рождения celebrantID в день day.
+// Возвращает false, если объявление уже опубликовано.
+func (r *Repo) Claim(channelID, celebrantID int, day time.Time) (bool, error) {
+	query := `
+		INSERT INTO announcement_log (channel_id, celebrant_id, birthday)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (channel_id, celebrant_id, birthday) DO NOTHING
+	`
+	tag, err := r.db.Exec(context.Background(), query, channelID, celebrantID, day.Format("2006-01-02"))
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Release снимает резерв после неудачной публикации, чтобы следующий цикл попробовал снова.
+func (r *Repo) Release(channelID, celebrantID int, day time.Time) error {
+	query := `DELETE FROM announcement_log WHERE channel_id = $1 AND celebrant_id = $2 AND birthday = $3`
+	_, err := r.db.Exec(context.Background(), query, channelID, celebrantID, day.Format("2006-01-02"))
+	return err
+}
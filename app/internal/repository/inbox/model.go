@@ -0,0 +1,18 @@
+package inbox
+
+import "time"
+
+// Item - уведомление во встроенном центре уведомлений пользователя (колокольчик в клиенте).
+type Item struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	Kind   string `json:"kind"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	// CelebrantID - именинник, 0 для дайджестов и сводок.
+	CelebrantID int `json:"celebrant_id"`
+	// EventID - идентификатор события уведомителя, защищает от повторной записи при ретраях.
+	EventID   string     `json:"event_id"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,99 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrNotFound = errors.New("inbox notification not found")
+
+const columns = `id, user_id, kind, title, body, COALESCE(celebrant_id, 0), event_id, read_at, created_at`
+
+type Repo struct {
+	db DBPool
+}
+
+func NewRepo(db DBPool) *Repo {
+	return &Repo{db: db}
+}
+
+// Add сохраняет уведомление и заполняет item.ID и item.CreatedAt.
+// Возвращает false, если событие уже было записано пользователю.
+func (r *Repo) Add(item *Item) (bool, error) {
+	query := `
+		INSERT INTO inbox (user_id, kind, title, body, celebrant_id, event_id)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6)
+		ON CONFLICT (user_id, event_id) DO NOTHING
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(context.Background(), query, item.UserID, item.Kind, item.Title, item.Body, item.CelebrantID, item.EventID).
+		Scan(&item.ID, &item.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// List возвращает страницу уведомлений пользователя, новые первыми.
+func (r *Repo) List(userID int, unreadOnly bool, limit, offset int) ([]Item, error) {
+	query := `
+		SELECT ` + columns + `
+		FROM inbox
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.Query(context.Background(), query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(&i.ID, &i.UserID, &i.Kind, &i.Title, &i.Body, &i.CelebrantID, &i.EventID, &i.ReadAt, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Counts возвращает общее число уведомлений пользователя и число непрочитанных.
+func (r *Repo) Counts(userID int) (total int, unread int, err error) {
+	query := `SELECT COUNT(*), COUNT(*) FILTER (WHERE read_at IS NULL) FROM inbox WHERE user_id = $1`
+	err = r.db.QueryRow(context.Background(), query, userID).Scan(&total, &unread)
+	return total, unread, err
+}
+
+// MarkRead отмечает уведомление прочитанным; повторная отметка не меняет время прочтения.
+func (r *Repo) MarkRead(userID, id int) error {
+	query := `UPDATE inbox SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`
+	tag, err := r.db.Exec(context.Background(), query, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя и возвращает их число.
+func (r *Repo) MarkAllRead(userID int) (int64, error) {
+	tag, err := r.db.Exec(context.Background(), `UPDATE inbox SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
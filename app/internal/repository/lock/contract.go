@@ -0,0 +1,10 @@
+package lock
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type DBPool interface {
+	Acquire(ctx context.Context) (*pgxpool.Conn, error)
+}
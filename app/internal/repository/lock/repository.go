@@ -0,0 +1,50 @@
+package lock
+
+import (
+	"context"
+	"log"
+)
+
+// Repo выдаёт сессионные advisory-блокировки Postgres.
+// Блокировка живёт, пока открыто соединение, поэтому при падении держателя
+// она освобождается автоматически и её может взять другой экземпляр.
+type Repo struct {
+	db DBPool
+}
+
+func NewRepo(db DBPool) *Repo {
+	return &Repo{db: db}
+}
+
+// TryLock пытается взять блокировку key без ожидания.
+// Если блокировка получена, вызывающий обязан вызвать unlock.
+func (r *Repo) TryLock(key int64) (unlock func(), acquired bool, err error) {
+	ctx := context.Background()
+
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		conn.Release()
+		return nil, false, err
+	}
+
+	if !acquired {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	unlock = func() {
+		if _, err := conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, key); err != nil {
+			// Закрываем соединение, чтобы Postgres точно снял блокировку
+			log.Printf("Error releasing advisory lock %d: %v", key, err)
+			if err := conn.Conn().Close(ctx); err != nil {
+				log.Printf("Error closing connection: %v", err)
+			}
+		}
+		conn.Release()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,52 @@
+package notification
+
+import "time"
+
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	// StatusDead - исчерпан лимит попыток, повторные отправки прекращены.
+	StatusDead = "dead"
+	// StatusSkipped - получатель отключил канал до повторной отправки.
+	StatusSkipped = "skipped"
+
+	KindReminder = "reminder"
+	// KindDigest - ежедневная сводка всех напоминаний подписчика одним сообщением.
+	KindDigest = "digest"
+	// KindDigestEntry - отметка о том, что напоминание уже вошло в один из дайджестов подписчика.
+	KindDigestEntry = "digest_entry"
+	// KindWeeklySummary и KindMonthlySummary - сводки дней рождения на неделю и на месяц.
+	KindWeeklySummary  = "weekly_summary"
+	KindMonthlySummary = "monthly_summary"
+)
+
+type Notification struct {
+	ID           int `json:"id"`
+	SubscriberID int `json:"subscriber_id"`
+	// CelebrantID равен 0 для сообщений, не относящихся к одному имениннику.
+	CelebrantID  int    `json:"celebrant_id"`
+	BirthdayYear int    `json:"birthday_year"`
+	OffsetDays   int    `json:"offset_days"`
+	Channel      string `json:"channel"`
+	Kind         string `json:"kind"`
+	// DedupeKey вместе с подписчиком и каналом определяет, что сообщение уже отправлялось.
+	DedupeKey     string     `json:"dedupe_key"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error"`
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	Payload       Payload    `json:"payload"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Payload - содержимое сообщения, сохраняемое для повторной отправки.
+type Payload struct {
+	Subject  string `json:"subject"`
+	Body     string `json:"body"`
+	HTMLBody string `json:"html_body,omitempty"`
+	// Reminders - ключи напоминаний, из которых собран дайджест; при повторе по ним исключаются
+	// напоминания по удалённым и приостановленным подпискам.
+	Reminders []string `json:"reminders,omitempty"`
+}
@@ -0,0 +1,171 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v4"
+	"time"
+)
+
+var ErrNotFound = errors.New("notification not found")
+
+const columns = `id, subscriber_id, COALESCE(celebrant_id, 0), birthday_year, offset_days, channel, kind, dedupe_key, status, attempts, last_error, next_attempt_at, payload, created_at, updated_at`
+
+type Repo struct {
+	db DBPool
+}
+
+func NewRepo(db DBPool) *Repo {
+	return &Repo{db: db}
+}
+
+func scanNotification(row pgx.Row, n *Notification) error {
+	return row.Scan(&n.ID, &n.SubscriberID, &n.CelebrantID, &n.BirthdayYear, &n.OffsetDays, &n.Channel, &n.Kind, &n.DedupeKey, &n.Status,
+		&n.Attempts, &n.LastError, &n.NextAttemptAt, &n.Payload, &n.CreatedAt, &n.UpdatedAt)
+}
+
+func collect(rows pgx.Rows) ([]Notification, error) {
+	defer rows.Close()
+
+	var notifications []Notification
+	for rows.Next() {
+		var n Notification
+		if err := scanNotification(rows, &n); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// Claim резервирует отправку сообщения с ключом n.DedupeKey и заполняет n.ID.
+// Возвращает false, если сообщение уже доставлено, ждёт повторной отправки
+// или сейчас отправляется другим запуском.
+func (r *Repo) Claim(n *Notification) (bool, error) {
+	// Забрать повторно можно только "зависшую" в pending запись, неудачные отправки обрабатывает очередь повторов
+	query := `
+		INSERT INTO notifications (subscriber_id, celebrant_id, birthday_year, offset_days, channel, kind, dedupe_key, status, payload)
+		VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7, 'pending', $8)
+		ON CONFLICT (subscriber_id, dedupe_key, channel) DO UPDATE
+		SET updated_at = NOW()
+		WHERE notifications.status = 'pending' AND notifications.updated_at < NOW() - INTERVAL '10 minutes'
+		RETURNING id, status, attempts
+	`
+	err := r.db.QueryRow(context.Background(), query, n.SubscriberID, n.CelebrantID, n.BirthdayYear, n.OffsetDays, n.Channel, n.Kind, n.DedupeKey, n.Payload).
+		Scan(&n.ID, &n.Status, &n.Attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *Repo) MarkSent(id int) error {
+	query := `
+		UPDATE notifications
+		SET status = 'sent', attempts = attempts + 1, last_error = NULL, next_attempt_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(context.Background(), query, id)
+	return err
+}
+
+// MarkFailed ставит напоминание в очередь на повторную отправку в nextAttemptAt.
+func (r *Repo) MarkFailed(id int, sendErr error, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE notifications
+		SET status = 'failed', attempts = attempts + 1, last_error = $2, next_attempt_at = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(context.Background(), query, id, sendErr.Error(), nextAttemptAt)
+	return err
+}
+
+// MarkSkipped снимает напоминание с очереди повторов без отправки.
+func (r *Repo) MarkSkipped(id int) error {
+	query := `
+		UPDATE notifications
+		SET status = 'skipped', next_attempt_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(context.Background(), query, id)
+	return err
+}
+
+// Postpone откладывает повторную отправку до until, не расходуя попытку.
+func (r *Repo) Postpone(id int, until time.Time) error {
+	query := `
+		UPDATE notifications
+		SET status = 'failed', next_attempt_at = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(context.Background(), query, id, until)
+	return err
+}
+
+func (r *Repo) MarkDead(id int, sendErr error) error {
+	query := `
+		UPDATE notifications
+		SET status = 'dead', attempts = attempts + 1, last_error = $2, next_attempt_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(context.Background(), query, id, sendErr.Error())
+	return err
+}
+
+// ClaimDue забирает из очереди до limit напоминаний, время повтора которых наступило.
+// SKIP LOCKED позволяет нескольким воркерам не мешать друг другу.
+func (r *Repo) ClaimDue(limit int) ([]Notification, error) {
+	query := `
+		UPDATE notifications
+		SET status = 'pending', updated_at = NOW()
+		WHERE id IN (
+			SELECT id
+			FROM notifications
+			WHERE status = 'failed' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + columns
+
+	rows, err := r.db.Query(context.Background(), query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collect(rows)
+}
+
+func (r *Repo) GetDead() ([]Notification, error) {
+	query := `SELECT ` + columns + ` FROM notifications WHERE status = 'dead' ORDER BY updated_at DESC`
+
+	rows, err := r.db.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	return collect(rows)
+}
+
+// Requeue возвращает "мёртвое" напоминание в очередь с обнулённым счётчиком попыток.
+func (r *Repo) Requeue(id int) error {
+	query := `
+		UPDATE notifications
+		SET status = 'failed', attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'dead'
+	`
+	tag, err := r.db.Exec(context.Background(), query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
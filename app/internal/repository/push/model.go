@@ -0,0 +1,16 @@
+package push
+
+import "time"
+
+// Subscription - подписка браузера или устройства на Web Push.
+type Subscription struct {
+	ID       int    `json:"id"`
+	UserID   int    `json:"user_id"`
+	Endpoint string `json:"endpoint"`
+	// P256dh и Auth - ключи шифрования сообщений из PushSubscription браузера, base64url.
+	P256dh string `json:"-"`
+	Auth   string `json:"-"`
+	// Device - подпись устройства для пользователя, например, «Chrome на ноутбуке».
+	Device    string    `json:"device"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,106 @@
+package push
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFound = errors.New("push subscription not found")
+
+const columns = `id, user_id, endpoint, p256dh, auth, device, created_at`
+
+type Repo struct {
+	db DBPool
+}
+
+func NewRepo(db DBPool) *Repo {
+	return &Repo{db: db}
+}
+
+// Save сохраняет подписку и заполняет s.ID и s.CreatedAt. Endpoint уникален для браузера,
+// поэтому повторная регистрация обновляет ключи и переносит подписку на текущего пользователя.
+func (r *Repo) Save(s *Subscription) error {
+	query := `
+		INSERT INTO push_subscriptions (user_id, endpoint, p256dh, auth, device)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (endpoint) DO UPDATE
+		SET user_id = EXCLUDED.user_id, p256dh = EXCLUDED.p256dh, auth = EXCLUDED.auth, device = EXCLUDED.device
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(context.Background(), query, s.UserID, s.Endpoint, s.P256dh, s.Auth, s.Device).Scan(&s.ID, &s.CreatedAt)
+}
+
+func (r *Repo) GetByUser(userID int) ([]Subscription, error) {
+	query := `SELECT ` + columns + ` FROM push_subscriptions WHERE user_id = $1 ORDER BY id`
+	return r.query(query, userID)
+}
+
+func (r *Repo) query(query string, args ...interface{}) ([]Subscription, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []Subscription
+	for rows.Next() {
+		var s Subscription
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Endpoint, &s.P256dh, &s.Auth, &s.Device, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetPending возвращает подписки пользователя, на которые событие eventID ещё не доставлено.
+func (r *Repo) GetPending(userID int, eventID string) ([]Subscription, error) {
+	query := `
+		SELECT ` + columns + `
+		FROM push_subscriptions s
+		WHERE s.user_id = $1
+		AND NOT EXISTS (
+			SELECT 1 FROM push_deliveries d
+			WHERE d.subscription_id = s.id AND d.event_id = $2
+		)
+		ORDER BY s.id
+	`
+	return r.query(query, userID, eventID)
+}
+
+// RecordDelivery отмечает, что событие eventID доставлено на подписку subscriptionID.
+func (r *Repo) RecordDelivery(subscriptionID int, eventID string) error {
+	query := `INSERT INTO push_deliveries (subscription_id, event_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	_, err := r.db.Exec(context.Background(), query, subscriptionID, eventID)
+	return err
+}
+
+func (r *Repo) HasSubscriptions(userID int) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM push_subscriptions WHERE user_id = $1)`
+	err := r.db.QueryRow(context.Background(), query, userID).Scan(&exists)
+	return exists, err
+}
+
+// Delete удаляет подписку пользователя; чужие подписки не находятся.
+func (r *Repo) Delete(userID, id int) error {
+	tag, err := r.db.Exec(context.Background(), `DELETE FROM push_subscriptions WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteByEndpoint удаляет подписку, которую push-сервис признал недействительной.
+func (r *Repo) DeleteByEndpoint(endpoint string) error {
+	_, err := r.db.Exec(context.Background(), `DELETE FROM push_subscriptions WHERE endpoint = $1`, endpoint)
+	return err
+}
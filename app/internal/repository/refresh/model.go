@@ -0,0 +1,16 @@
+package refresh
+
+import "time"
+
+// Token - refresh-токен; сам токен не хранится, только его хеш.
+type Token struct {
+	ID     int `json:"id"`
+	UserID int `json:"user_id"`
+	// FamilyID объединяет токены, полученные ротацией из одного входа.
+	FamilyID  string     `json:"family_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
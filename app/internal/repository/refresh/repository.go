@@ -0,0 +1,68 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrNotFound = errors.New("refresh token not found")
+
+const columns = `id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at`
+
+type Repo struct {
+	db DBPool
+}
+
+func NewRepo(db DBPool) *Repo {
+	return &Repo{db: db}
+}
+
+// Create сохраняет токен и заполняет t.ID и t.CreatedAt.
+func (r *Repo) Create(t *Token) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(context.Background(), query, t.UserID, t.FamilyID, t.TokenHash, t.ExpiresAt).Scan(&t.ID, &t.CreatedAt)
+}
+
+func (r *Repo) GetByHash(hash string) (*Token, error) {
+	query := `SELECT ` + columns + ` FROM refresh_tokens WHERE token_hash=$1`
+	var t Token
+	err := r.db.QueryRow(context.Background(), query, hash).
+		Scan(&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.UsedAt, &t.RevokedAt, &t.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// MarkUsed помечает токен использованным. Возвращает false, если его уже использовали
+// или отозвали - например, параллельный запрос с тем же токеном успел первым.
+func (r *Repo) MarkUsed(id int) (bool, error) {
+	query := `UPDATE refresh_tokens SET used_at=NOW() WHERE id=$1 AND used_at IS NULL AND revoked_at IS NULL`
+	tag, err := r.db.Exec(context.Background(), query, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// RevokeFamily отзывает все токены цепочки ротаций.
+func (r *Repo) RevokeFamily(familyID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at=NOW() WHERE family_id=$1 AND revoked_at IS NULL`
+	_, err := r.db.Exec(context.Background(), query, familyID)
+	return err
+}
+
+// RevokeUser отзывает все refresh-токены пользователя.
+func (r *Repo) RevokeUser(userID int) error {
+	query := `UPDATE refresh_tokens SET revoked_at=NOW() WHERE user_id=$1 AND revoked_at IS NULL`
+	_, err := r.db.Exec(context.Background(), query, userID)
+	return err
+}
@@ -0,0 +1,80 @@
+package revocation
+
+import (
+	"context"
+	"time"
+)
+
+type Repo struct {
+	db DBPool
+}
+
+func NewRepo(db DBPool) *Repo {
+	return &Repo{db: db}
+}
+
+// RevokeToken заносит JWT с идентификатором jti в список отозванных до его истечения.
+func (r *Repo) RevokeToken(jti string, userID int, expiresAt time.Time) error {
+	query := `INSERT INTO revoked_tokens (jti, user_id, expires_at) VALUES ($1, $2, $3) ON CONFLICT (jti) DO NOTHING`
+	_, err := r.db.Exec(context.Background(), query, jti, userID, expiresAt)
+	return err
+}
+
+// RevokeUserTokens отзывает все JWT пользователя, выданные не позже before.
+func (r *Repo) RevokeUserTokens(userID int, before time.Time) error {
+	query := `
+		INSERT INTO user_token_revocations (user_id, revoked_before) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before)
+	`
+	_, err := r.db.Exec(context.Background(), query, userID, before)
+	return err
+}
+
+// GetRevokedTokens возвращает еще не истекшие отозванные токены: jti -> срок действия.
+func (r *Repo) GetRevokedTokens() (map[string]time.Time, error) {
+	rows, err := r.db.Query(context.Background(), `SELECT jti, expires_at FROM revoked_tokens WHERE expires_at > NOW()`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make(map[string]time.Time)
+	for rows.Next() {
+		var jti string
+		var expiresAt time.Time
+		if err := rows.Scan(&jti, &expiresAt); err != nil {
+			return nil, err
+		}
+		tokens[jti] = expiresAt
+	}
+	return tokens, rows.Err()
+}
+
+// GetUserRevocations возвращает отзывы всех токенов пользователей, сделанные позже since: user_id -> граница.
+func (r *Repo) GetUserRevocations(since time.Time) (map[int]time.Time, error) {
+	rows, err := r.db.Query(context.Background(), `SELECT user_id, revoked_before FROM user_token_revocations WHERE revoked_before > $1`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make(map[int]time.Time)
+	for rows.Next() {
+		var userID int
+		var before time.Time
+		if err := rows.Scan(&userID, &before); err != nil {
+			return nil, err
+		}
+		users[userID] = before
+	}
+	return users, rows.Err()
+}
+
+// DeleteExpired удаляет истекшие записи и отзывы всех токенов, сделанные раньше since.
+func (r *Repo) DeleteExpired(since time.Time) error {
+	if _, err := r.db.Exec(context.Background(), `DELETE FROM revoked_tokens WHERE expires_at <= NOW()`); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(context.Background(), `DELETE FROM user_token_revocations WHERE revoked_before <= $1`, since)
+	return err
+}
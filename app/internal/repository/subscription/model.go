@@ -0,0 +1,31 @@
+package subscription
+
+import (
+	"birthdayReminder/internal/repository/user"
+	"time"
+)
+
+type Subscription struct {
+	ID            int   `json:"id"`
+	UserID        int   `json:"user_id"`
+	RelatedUserID int   `json:"related_user_id"`
+	Offsets       []int `json:"offsets"`
+	// SnoozedUntil - день, с которого напоминания возобновляются; nil - подписка активна.
+	SnoozedUntil *time.Time `json:"snoozed_until"`
+}
+
+// Details - подписка вместе с подписчиком и именинником, используется при рассылке напоминаний.
+type Details struct {
+	Subscription
+	Subscriber user.User
+	Celebrant  user.User
+}
+
+// SnoozedOn сообщает, приостановлены ли напоминания в день day (учитывается только дата).
+func (s Subscription) SnoozedOn(day time.Time) bool {
+	if s.SnoozedUntil == nil {
+		return false
+	}
+	until := time.Date(s.SnoozedUntil.Year(), s.SnoozedUntil.Month(), s.SnoozedUntil.Day(), 0, 0, 0, 0, day.Location())
+	return day.Before(until)
+}
@@ -3,8 +3,12 @@ package subscription
 import (
 	"context"
 	"errors"
+	"github.com/jackc/pgx/v4"
+	"time"
 )
 
+var ErrNotFound = errors.New("subscription not found")
+
 type Repo struct {
 	db DBPool
 }
@@ -13,7 +17,7 @@ func NewRepo(db DBPool) *Repo {
 	return &Repo{db: db}
 }
 
-func (r *Repo) CreateSubscription(userID int, relatedUserID int) error {
+func (r *Repo) CreateSubscription(userID int, relatedUserID int, offsets []int) error {
 	//проверяем, существует ли подписка
 	queryCheck := `SELECT EXISTS(SELECT 1 FROM subscriptions WHERE user_id=$1 AND related_user_id=$2)`
 	var exists bool
@@ -27,8 +31,8 @@ func (r *Repo) CreateSubscription(userID int, relatedUserID int) error {
 	}
 
 	// Если подписка не существует, создаем новую
-	queryInsert := `INSERT INTO subscriptions (user_id, related_user_id) VALUES ($1, $2)`
-	_, err = r.db.Exec(context.Background(), queryInsert, userID, relatedUserID)
+	queryInsert := `INSERT INTO subscriptions (user_id, related_user_id, offsets) VALUES ($1, $2, $3)`
+	_, err = r.db.Exec(context.Background(), queryInsert, userID, relatedUserID, offsets)
 	return err
 }
 
@@ -54,3 +58,120 @@ func (r *Repo) UnsubscribeUser(userID int, relatedUserID int) error {
 
 	return nil
 }
+
+func (r *Repo) GetSubscriptions(userID int) ([]Subscription, error) {
+	query := `SELECT id, user_id, related_user_id, offsets, snoozed_until FROM subscriptions WHERE user_id=$1 ORDER BY id`
+	rows, err := r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []Subscription
+	for rows.Next() {
+		var s Subscription
+		if err := rows.Scan(&s.ID, &s.UserID, &s.RelatedUserID, &s.Offsets, &s.SnoozedUntil); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// UpdateOffsets меняет сроки напоминаний; подписка должна принадлежать userID.
+func (r *Repo) UpdateOffsets(id int, userID int, offsets []int) error {
+	query := `UPDATE subscriptions SET offsets=$3 WHERE id=$1 AND user_id=$2`
+	tag, err := r.db.Exec(context.Background(), query, id, userID, offsets)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetSubscription возвращает подписку id, если она принадлежит userID.
+func (r *Repo) GetSubscription(id int, userID int) (*Subscription, error) {
+	query := `SELECT id, user_id, related_user_id, offsets, snoozed_until FROM subscriptions WHERE id=$1 AND user_id=$2`
+	var s Subscription
+	err := r.db.QueryRow(context.Background(), query, id, userID).Scan(&s.ID, &s.UserID, &s.RelatedUserID, &s.Offsets, &s.SnoozedUntil)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Snooze приостанавливает напоминания до дня until; nil снимает паузу.
+func (r *Repo) Snooze(id int, userID int, until *time.Time) error {
+	query := `UPDATE subscriptions SET snoozed_until=$3 WHERE id=$1 AND user_id=$2`
+	tag, err := r.db.Exec(context.Background(), query, id, userID, until)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetByUsers возвращает подписку userID на relatedUserID.
+func (r *Repo) GetByUsers(userID int, relatedUserID int) (*Subscription, error) {
+	query := `SELECT id, user_id, related_user_id, offsets, snoozed_until FROM subscriptions WHERE user_id=$1 AND related_user_id=$2`
+	var s Subscription
+	err := r.db.QueryRow(context.Background(), query, userID, relatedUserID).Scan(&s.ID, &s.UserID, &s.RelatedUserID, &s.Offsets, &s.SnoozedUntil)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+const detailsQuery = `
+		SELECT s.id, s.user_id, s.related_user_id, s.offsets, s.snoozed_until,
+			su.id, su.name, su.email, su.date_of_birth, su.time_zone, su.notify_hour, su.locale, su.delivery_mode, COALESCE(su.telegram_chat_id, 0), su.disabled_channels, su.quiet_start, su.quiet_end, su.email_verified,
+			c.id, c.name, c.email, c.date_of_birth, c.leap_policy
+		FROM subscriptions s
+		JOIN users su ON su.id = s.user_id
+		JOIN users c ON c.id = s.related_user_id
+	`
+
+func (r *Repo) GetAllDetails() ([]Details, error) {
+	return r.queryDetails(detailsQuery)
+}
+
+func (r *Repo) queryDetails(query string, args ...interface{}) ([]Details, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var details []Details
+	for rows.Next() {
+		var d Details
+		err := rows.Scan(&d.ID, &d.UserID, &d.RelatedUserID, &d.Offsets, &d.SnoozedUntil,
+			&d.Subscriber.ID, &d.Subscriber.Name, &d.Subscriber.Email, &d.Subscriber.DateOfBirth, &d.Subscriber.TimeZone, &d.Subscriber.NotifyHour, &d.Subscriber.Locale, &d.Subscriber.DeliveryMode, &d.Subscriber.TelegramChatID, &d.Subscriber.DisabledChannels, &d.Subscriber.QuietStart, &d.Subscriber.QuietEnd, &d.Subscriber.EmailVerified,
+			&d.Celebrant.ID, &d.Celebrant.Name, &d.Celebrant.Email, &d.Celebrant.DateOfBirth, &d.Celebrant.LeapPolicy)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
@@ -0,0 +1,10 @@
+package telegram
+
+import "time"
+
+// LinkCode - одноразовый код привязки Telegram-чата к аккаунту.
+type LinkCode struct {
+	Code      string    `json:"code"`
+	UserID    int       `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
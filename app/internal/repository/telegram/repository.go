@@ -0,0 +1,59 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrInvalidCode = errors.New("link code is invalid or expired")
+
+type Repo struct {
+	db DBPool
+}
+
+func NewRepo(db DBPool) *Repo {
+	return &Repo{db: db}
+}
+
+// CreateLinkCode сохраняет новый код привязки, прежние коды пользователя перестают действовать.
+func (r *Repo) CreateLinkCode(code LinkCode) error {
+	query := `
+		WITH removed AS (DELETE FROM telegram_link_codes WHERE user_id = $2 OR expires_at < NOW())
+		INSERT INTO telegram_link_codes (code, user_id, expires_at) VALUES ($1, $2, $3)
+	`
+	_, err := r.db.Exec(context.Background(), query, code.Code, code.UserID, code.ExpiresAt)
+	return err
+}
+
+// ConsumeLinkCode погашает код и привязывает чат chatID к его владельцу.
+// Возвращает ErrInvalidCode, если код не найден или истёк.
+func (r *Repo) ConsumeLinkCode(code string, chatID int64) (int, error) {
+	query := `
+		WITH link AS (
+			DELETE FROM telegram_link_codes
+			WHERE code = $1 AND expires_at > NOW()
+			RETURNING user_id
+		)
+		UPDATE users SET telegram_chat_id = $2
+		FROM link
+		WHERE users.id = link.user_id
+		RETURNING users.id
+	`
+	var userID int
+	err := r.db.QueryRow(context.Background(), query, code, chatID).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrInvalidCode
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// Unlink отвязывает Telegram-чат от аккаунта.
+func (r *Repo) Unlink(userID int) error {
+	query := `UPDATE users SET telegram_chat_id = NULL WHERE id = $1`
+	_, err := r.db.Exec(context.Background(), query, userID)
+	return err
+}
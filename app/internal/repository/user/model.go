@@ -2,10 +2,41 @@ package user
 
 import "time"
 
+const (
+	DefaultTimeZone = "Europe/Moscow"
+	DefaultLocale   = "ru"
+
+	// DeliveryIndividual - отдельное письмо на каждое напоминание, DeliveryDigest - одно письмо в день со всеми напоминаниями.
+	DeliveryIndividual = "individual"
+	DeliveryDigest     = "digest"
+)
+
 type User struct {
 	ID          int       `json:"id"`
 	Name        string    `json:"name"`
 	Email       string    `json:"email"`
 	Password    string    `json:"password"`
 	DateOfBirth time.Time `json:"date_of_birth"`
+	IsAdmin     bool      `json:"-"`
+	TimeZone    string    `json:"time_zone"`
+	NotifyHour  int       `json:"notify_hour"`
+	// LeapPolicy - когда праздновать 29 февраля в невисокосный год (feb28 или mar1).
+	LeapPolicy string `json:"leap_policy"`
+	// Locale - язык уведомлений (ru, en).
+	Locale string `json:"locale"`
+	// DeliveryMode - individual или digest.
+	DeliveryMode string `json:"delivery_mode"`
+	// WeeklySummary и MonthlySummary - подписка на сводки дней рождения по понедельникам и первого числа.
+	WeeklySummary  bool `json:"weekly_summary"`
+	MonthlySummary bool `json:"monthly_summary"`
+	// TelegramChatID - привязанный Telegram-чат, 0 если не привязан.
+	TelegramChatID int64 `json:"-"`
+	// DisabledChannels - каналы доставки, которые пользователь отключил.
+	DisabledChannels []string `json:"disabled_channels"`
+	// QuietStart и QuietEnd - период тишины по местному времени, часы [QuietStart, QuietEnd).
+	// Одинаковые значения - периода нет.
+	QuietStart int `json:"quiet_start"`
+	QuietEnd   int `json:"quiet_end"`
+	// EmailVerified - адрес подтвержден по ссылке из письма; до этого email-уведомления не отправляются.
+	EmailVerified bool `json:"-"`
 }
@@ -0,0 +1,29 @@
+package user
+
+// ChannelEnabled сообщает, разрешил ли пользователь доставку по каналу.
+// Каналы включены по умолчанию, поэтому новый канал не требует миграции настроек.
+func (u User) ChannelEnabled(channel string) bool {
+	for _, disabled := range u.DisabledChannels {
+		if disabled == channel {
+			return false
+		}
+	}
+	return true
+}
+
+// HasQuietHours сообщает, задан ли период тишины; одинаковые начало и конец означают, что его нет.
+func (u User) HasQuietHours() bool {
+	return u.QuietStart != u.QuietEnd
+}
+
+// InQuietHours сообщает, попадает ли час по местному времени пользователя в период тишины
+// [QuietStart, QuietEnd). Период может переходить через полночь, например, с 22 до 8.
+func (u User) InQuietHours(hour int) bool {
+	if !u.HasQuietHours() {
+		return false
+	}
+	if u.QuietStart < u.QuietEnd {
+		return hour >= u.QuietStart && hour < u.QuietEnd
+	}
+	return hour >= u.QuietStart || hour < u.QuietEnd
+}
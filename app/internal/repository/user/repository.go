@@ -1,10 +1,15 @@
 package user
 
 import (
+	"birthdayReminder/internal/birthday"
+	"github.com/jackc/pgx/v4"
 	"golang.org/x/net/context"
 	"time"
 )
 
+// columns - поля пользователя без пароля, порядок совпадает с fields.
+const columns = `id, name, email, date_of_birth, is_admin, time_zone, notify_hour, leap_policy, locale, delivery_mode, weekly_summary, monthly_summary, COALESCE(telegram_chat_id, 0), disabled_channels, quiet_start, quiet_end, email_verified`
+
 type Repo struct {
 	db DBPool
 }
@@ -13,9 +18,52 @@ func NewRepo(db DBPool) *Repo {
 	return &Repo{db: db}
 }
 
+func (u *User) fields() []interface{} {
+	return []interface{}{&u.ID, &u.Name, &u.Email, &u.DateOfBirth, &u.IsAdmin, &u.TimeZone, &u.NotifyHour, &u.LeapPolicy, &u.Locale, &u.DeliveryMode, &u.WeeklySummary, &u.MonthlySummary, &u.TelegramChatID, &u.DisabledChannels, &u.QuietStart, &u.QuietEnd, &u.EmailVerified}
+}
+
+func scanUser(row pgx.Row, user *User, extra ...interface{}) error {
+	return row.Scan(append(user.fields(), extra...)...)
+}
+
+func collectUsers(rows pgx.Rows) ([]User, error) {
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := scanUser(rows, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 func (r *Repo) CreateUser(user *User, hashedPassword []byte) error {
-	query := `INSERT INTO users (name, email, password, date_of_birth) VALUES ($1, $2, $3, $4)`
-	_, err := r.db.Exec(context.Background(), query, user.Name, user.Email, hashedPassword, user.DateOfBirth)
+	query := `INSERT INTO users (name, email, password, date_of_birth, time_zone, leap_policy, locale, delivery_mode) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	timeZone := user.TimeZone
+	if timeZone == "" {
+		timeZone = DefaultTimeZone
+	}
+	leapPolicy := user.LeapPolicy
+	if leapPolicy == "" {
+		leapPolicy = string(birthday.DefaultPolicy)
+	}
+	locale := user.Locale
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	deliveryMode := user.DeliveryMode
+	if deliveryMode == "" {
+		deliveryMode = DeliveryIndividual
+	}
+	err := r.db.QueryRow(context.Background(), query, user.Name, user.Email, hashedPassword, user.DateOfBirth, timeZone, leapPolicy, locale, deliveryMode).Scan(&user.ID)
 	if err != nil {
 		return err
 	}
@@ -24,14 +72,38 @@ func (r *Repo) CreateUser(user *User, hashedPassword []byte) error {
 
 func (r *Repo) GetUserByEmail(email string) (*User, error) {
 	var user User
-	query := `SELECT id, name, email, password, date_of_birth FROM users WHERE email=$1`
-	err := r.db.QueryRow(context.Background(), query, email).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.DateOfBirth)
+	query := `SELECT ` + columns + `, password FROM users WHERE email=$1`
+	err := scanUser(r.db.QueryRow(context.Background(), query, email), &user, &user.Password)
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
+func (r *Repo) GetUserByID(id int) (*User, error) {
+	var user User
+	query := `SELECT ` + columns + ` FROM users WHERE id=$1`
+	err := scanUser(r.db.QueryRow(context.Background(), query, id), &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdatePreferences сохраняет настройки доставки уведомлений.
+func (r *Repo) UpdatePreferences(user *User) error {
+	query := `UPDATE users SET notify_hour=$2, locale=$3, delivery_mode=$4, disabled_channels=COALESCE($5::text[], '{}'), quiet_start=$6, quiet_end=$7 WHERE id=$1`
+	_, err := r.db.Exec(context.Background(), query, user.ID, user.NotifyHour, user.Locale, user.DeliveryMode, user.DisabledChannels, user.QuietStart, user.QuietEnd)
+	return err
+}
+
+// UpdateProfile сохраняет изменяемые пользователем поля профиля.
+func (r *Repo) UpdateProfile(user *User) error {
+	query := `UPDATE users SET name=$2, date_of_birth=$3, time_zone=$4, notify_hour=$5, leap_policy=$6, locale=$7, delivery_mode=$8, weekly_summary=$9, monthly_summary=$10 WHERE id=$1`
+	_, err := r.db.Exec(context.Background(), query, user.ID, user.Name, user.DateOfBirth, user.TimeZone, user.NotifyHour, user.LeapPolicy, user.Locale, user.DeliveryMode, user.WeeklySummary, user.MonthlySummary)
+	return err
+}
+
 func (r *Repo) GetAvailableUsersForSubscription(userID int) ([]User, error) {
 	query := `
 		SELECT id, name, email, date_of_birth
@@ -65,38 +137,55 @@ func (r *Repo) GetAvailableUsersForSubscription(userID int) ([]User, error) {
 	return users, nil
 }
 
-func (r *Repo) GetUsersWithBirthdayTomorrow() ([]User, error) {
-	// Получаем завтрашнюю дату
-	tomorrow := time.Now().AddDate(0, 0, 1)
-	tomorrowMonth := int(tomorrow.Month())
-	tomorrowDay := tomorrow.Day()
+// GetUsersWithBirthdayBetween возвращает пользователей, чей день рождения празднуется в один из дней
+// с from по to включительно. Диапазон может переходить через конец года, но должен быть короче года.
+func (r *Repo) GetUsersWithBirthdayBetween(from, to time.Time) ([]User, error) {
+	fromDay := int(from.Month())*100 + from.Day()
+	toDay := int(to.Month())*100 + to.Day()
+
+	// Сравниваем "месяц*100 + день"; если диапазон переходит через Новый год, он распадается на два отрезка
+	monthDay := `(EXTRACT(MONTH FROM date_of_birth) * 100 + EXTRACT(DAY FROM date_of_birth))`
+	condition := monthDay + ` BETWEEN $1 AND $2`
+	if fromDay > toDay {
+		condition = monthDay + ` >= $1 OR ` + monthDay + ` <= $2`
+	}
 
+	// Родившиеся 29 февраля выбираются всегда, окончательно дату проверяет birthday.Next
 	query := `
-		SELECT id, name, email, date_of_birth
+		SELECT ` + columns + `
 		FROM users
-		WHERE EXTRACT(MONTH FROM date_of_birth) = $1 AND EXTRACT(DAY FROM date_of_birth) = $2
+		WHERE ` + condition + `
+		OR (EXTRACT(MONTH FROM date_of_birth) = 2 AND EXTRACT(DAY FROM date_of_birth) = 29)
 	`
-	// Выполняем запрос с завтрашним месяцем и днем
-	rows, err := r.db.Query(context.Background(), query, tomorrowMonth, tomorrowDay)
+	rows, err := r.db.Query(context.Background(), query, fromDay, toDay)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
+	candidates, err := collectUsers(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	lastDay := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, from.Location())
 	var users []User
-	for rows.Next() {
-		var user User
-		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.DateOfBirth); err != nil {
-			return nil, err
+	for _, user := range candidates {
+		if !birthday.Next(user.DateOfBirth, from, birthday.Policy(user.LeapPolicy)).After(lastDay) {
+			users = append(users, user)
 		}
-		users = append(users, user)
 	}
+	return users, nil
+}
 
-	if err := rows.Err(); err != nil {
+// GetSummaryRecipients возвращает пользователей, подписанных хотя бы на одну сводку.
+func (r *Repo) GetSummaryRecipients() ([]User, error) {
+	query := `SELECT ` + columns + ` FROM users WHERE weekly_summary OR monthly_summary`
+
+	rows, err := r.db.Query(context.Background(), query)
+	if err != nil {
 		return nil, err
 	}
-
-	return users, nil
+	return collectUsers(rows)
 }
 
 func (r *Repo) GetSubscribers(userID int) ([]User, error) {
@@ -127,3 +216,32 @@ func (r *Repo) GetSubscribers(userID int) ([]User, error) {
 
 	return subscribers, nil
 }
+
+// StartVerification запоминает jti новой ссылки подтверждения, если адрес еще не подтвержден
+// и предыдущая ссылка отправлена не раньше чем interval назад. false - отправлять рано или уже нечего.
+func (r *Repo) StartVerification(userID int, tokenID string, interval time.Duration) (bool, error) {
+	query := `
+		UPDATE users SET verification_token_id=$2, verification_sent_at=NOW()
+		WHERE id=$1 AND NOT email_verified
+		AND (verification_sent_at IS NULL OR verification_sent_at <= NOW() - $3 * INTERVAL '1 second')
+	`
+	tag, err := r.db.Exec(context.Background(), query, userID, tokenID, int(interval.Seconds()))
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// VerifyEmail подтверждает адрес email по ссылке с jti tokenID. Ссылка срабатывает один раз:
+// jti сбрасывается, а ссылка на прежний адрес или вытесненная повторной отправкой не подходит.
+func (r *Repo) VerifyEmail(userID int, email, tokenID string) (bool, error) {
+	query := `
+		UPDATE users SET email_verified=TRUE, verification_token_id=NULL
+		WHERE id=$1 AND email=$2 AND verification_token_id=$3 AND NOT email_verified
+	`
+	tag, err := r.db.Exec(context.Background(), query, userID, email, tokenID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
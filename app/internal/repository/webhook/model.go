@@ -0,0 +1,27 @@
+package webhook
+
+import "time"
+
+type Webhook struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	URL    string `json:"url"`
+	// Secret - ключ HMAC-подписи запросов, показывается владельцу только при создании.
+	Secret string `json:"-"`
+	// IsGlobal - вебхук администратора, получающий события всех пользователей.
+	IsGlobal  bool      `json:"is_global"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Delivery - запись истории доставки события на вебхук.
+type Delivery struct {
+	ID         int       `json:"id"`
+	WebhookID  int       `json:"webhook_id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	StatusCode *int      `json:"status_code"`
+	Error      *string   `json:"error"`
+	Success    bool      `json:"success"`
+	DurationMs int       `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrNotFound = errors.New("webhook not found")
+
+const columns = `id, user_id, url, secret, is_global, created_at`
+
+type Repo struct {
+	db DBPool
+}
+
+func NewRepo(db DBPool) *Repo {
+	return &Repo{db: db}
+}
+
+func scanWebhook(row pgx.Row, w *Webhook) error {
+	return row.Scan(&w.ID, &w.UserID, &w.URL, &w.Secret, &w.IsGlobal, &w.CreatedAt)
+}
+
+func collect(rows pgx.Rows) ([]Webhook, error) {
+	defer rows.Close()
+
+	var webhooks []Webhook
+	for rows.Next() {
+		var w Webhook
+		if err := scanWebhook(rows, &w); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// Create сохраняет вебхук и заполняет w.ID и w.CreatedAt.
+func (r *Repo) Create(w *Webhook) error {
+	query := `INSERT INTO webhooks (user_id, url, secret, is_global) VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	return r.db.QueryRow(context.Background(), query, w.UserID, w.URL, w.Secret, w.IsGlobal).Scan(&w.ID, &w.CreatedAt)
+}
+
+func (r *Repo) GetByID(id int) (*Webhook, error) {
+	var w Webhook
+	query := `SELECT ` + columns + ` FROM webhooks WHERE id = $1`
+	err := scanWebhook(r.db.QueryRow(context.Background(), query, id), &w)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// GetVisible возвращает вебхуки пользователя, а администратору - ещё и все глобальные.
+func (r *Repo) GetVisible(userID int, isAdmin bool) ([]Webhook, error) {
+	query := `SELECT ` + columns + ` FROM webhooks WHERE user_id = $1 OR ($2 AND is_global) ORDER BY id`
+
+	rows, err := r.db.Query(context.Background(), query, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return collect(rows)
+}
+
+func (r *Repo) Delete(id int) error {
+	tag, err := r.db.Exec(context.Background(), `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// HasTargets сообщает, есть ли вебхуки, которые получают события пользователя.
+func (r *Repo) HasTargets(userID int) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM webhooks WHERE user_id = $1 OR is_global)`
+	err := r.db.QueryRow(context.Background(), query, userID).Scan(&exists)
+	return exists, err
+}
+
+// GetTargets возвращает вебхуки пользователя и глобальные вебхуки, которым событие eventID
+// ещё не было успешно доставлено, чтобы повторная отправка не дублировала его.
+func (r *Repo) GetTargets(userID int, eventID string) ([]Webhook, error) {
+	query := `
+		SELECT ` + columns + `
+		FROM webhooks w
+		WHERE (w.user_id = $1 OR w.is_global)
+		AND NOT EXISTS (
+			SELECT 1 FROM webhook_deliveries d
+			WHERE d.webhook_id = w.id AND d.event_id = $2 AND d.success
+		)
+		ORDER BY w.id
+	`
+	rows, err := r.db.Query(context.Background(), query, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return collect(rows)
+}
+
+func (r *Repo) RecordDelivery(d *Delivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, status_code, error, success, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(context.Background(), query, d.WebhookID, d.EventID, d.EventType, d.StatusCode, d.Error, d.Success, d.DurationMs).
+		Scan(&d.ID, &d.CreatedAt)
+}
+
+// GetDeliveries возвращает последние limit попыток доставки на вебхук, новые первыми.
+func (r *Repo) GetDeliveries(webhookID int, limit int) ([]Delivery, error) {
+	query := `
+		SELECT id, webhook_id, event_id, event_type, status_code, error, success, duration_ms, created_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(context.Background(), query, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var d Delivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.StatusCode, &d.Error, &d.Success, &d.DurationMs, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,10 @@
+package stream
+
+import "context"
+
+// Broker доставляет события между экземплярами сервиса.
+type Broker interface {
+	Publish(ctx context.Context, channel, payload string) error
+	// Listen блокируется до отмены ctx или ошибки, вызывая handle на каждое сообщение канала.
+	Listen(ctx context.Context, channel string, handle func(payload string)) error
+}
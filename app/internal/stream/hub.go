@@ -0,0 +1,152 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// Channel - канал Postgres NOTIFY, через который экземпляры обмениваются событиями.
+const Channel = "birthday_events"
+
+// Типы событий потока.
+const (
+	EventNotification = "notification"
+	EventSubscription = "subscription"
+	EventGreeting     = "greeting"
+)
+
+const (
+	clientBuffer     = 16
+	listenRetryDelay = 5 * time.Second
+	publishTimeout   = 5 * time.Second
+)
+
+// Event - событие для конкретного пользователя. Data должна быть небольшой:
+// полезная нагрузка NOTIFY ограничена 8000 байт, подробности клиент получает через API.
+type Event struct {
+	Type   string          `json:"type"`
+	UserID int             `json:"user_id"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Hub раздаёт события подключённым клиентам. Publish отправляет событие через брокер,
+// поэтому его получают клиенты всех экземпляров, включая текущий.
+type Hub struct {
+	broker Broker
+
+	mu      sync.RWMutex
+	clients map[int]map[chan Event]struct{}
+	closed  bool
+}
+
+func NewHub(broker Broker) *Hub {
+	return &Hub{broker: broker, clients: make(map[int]map[chan Event]struct{})}
+}
+
+// Subscribe подключает клиента пользователя userID. Вызывающий обязан вызвать unsubscribe.
+func (h *Hub) Subscribe(userID int) (events <-chan Event, unsubscribe func()) {
+	ch := make(chan Event, clientBuffer)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[chan Event]struct{})
+	}
+	h.clients[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.clients[userID], ch)
+			if len(h.clients[userID]) == 0 {
+				delete(h.clients, userID)
+			}
+			h.mu.Unlock()
+		})
+	}
+}
+
+// Close отключает всех клиентов, закрывая их каналы, чтобы открытые потоки завершились
+// при остановке сервера: http.Server.Shutdown не прерывает активные запросы.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for userID, clients := range h.clients {
+		for ch := range clients {
+			close(ch)
+		}
+		delete(h.clients, userID)
+	}
+}
+
+// Publish отправляет событие; без брокера оно раздаётся только клиентам этого экземпляра.
+func (h *Hub) Publish(e Event) error {
+	if h.broker == nil {
+		h.dispatch(e)
+		return nil
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	return h.broker.Publish(ctx, Channel, string(payload))
+}
+
+// Run слушает брокер до отмены ctx, переподключаясь после ошибок.
+func (h *Hub) Run(ctx context.Context) {
+	if h.broker == nil {
+		return
+	}
+
+	for {
+		err := h.broker.Listen(ctx, Channel, h.handlePayload)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Event stream listener stopped: %v, reconnecting in %s\n", err, listenRetryDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (h *Hub) handlePayload(payload string) {
+	var e Event
+	if err := json.Unmarshal([]byte(payload), &e); err != nil {
+		log.Println("Error decoding stream event:", err)
+		return
+	}
+	h.dispatch(e)
+}
+
+// dispatch не блокируется: медленный клиент теряет события, а не задерживает остальных.
+func (h *Hub) dispatch(e Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.clients[e.UserID] {
+		select {
+		case ch <- e:
+		default:
+			log.Printf("Dropping %s event for user ID %d: client is too slow\n", e.Type, e.UserID)
+		}
+	}
+}
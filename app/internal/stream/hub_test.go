@@ -0,0 +1,126 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryBroker имитирует LISTEN/NOTIFY: сообщение получают все слушатели канала.
+type memoryBroker struct {
+	mu        sync.Mutex
+	listeners map[string][]chan string
+	ready     chan struct{}
+}
+
+func newMemoryBroker(listeners int) *memoryBroker {
+	return &memoryBroker{listeners: make(map[string][]chan string), ready: make(chan struct{}, listeners)}
+}
+
+func (b *memoryBroker) Publish(ctx context.Context, channel, payload string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, l := range b.listeners[channel] {
+		l <- payload
+	}
+	return nil
+}
+
+func (b *memoryBroker) Listen(ctx context.Context, channel string, handle func(payload string)) error {
+	l := make(chan string, 16)
+	b.mu.Lock()
+	b.listeners[channel] = append(b.listeners[channel], l)
+	b.mu.Unlock()
+	b.ready <- struct{}{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case payload := <-l:
+			handle(payload)
+		}
+	}
+}
+
+func receive(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("event was not delivered")
+		return Event{}
+	}
+}
+
+func TestHubFanOutAcrossInstances(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broker := newMemoryBroker(2)
+	first, second := NewHub(broker), NewHub(broker)
+	go first.Run(ctx)
+	go second.Run(ctx)
+	<-broker.ready
+	<-broker.ready
+
+	annaOnFirst, unsubscribeFirst := first.Subscribe(1)
+	defer unsubscribeFirst()
+	annaOnSecond, unsubscribeSecond := second.Subscribe(1)
+	defer unsubscribeSecond()
+	ivan, unsubscribeIvan := second.Subscribe(2)
+	defer unsubscribeIvan()
+
+	require.NoError(t, first.Publish(Event{Type: EventNotification, UserID: 1, Data: json.RawMessage(`{"id":7}`)}))
+
+	for _, events := range []<-chan Event{annaOnFirst, annaOnSecond} {
+		e := receive(t, events)
+		assert.Equal(t, EventNotification, e.Type)
+		assert.JSONEq(t, `{"id":7}`, string(e.Data))
+	}
+	assert.Empty(t, ivan)
+}
+
+func TestHubUnsubscribe(t *testing.T) {
+	hub := NewHub(nil)
+
+	events, unsubscribe := hub.Subscribe(1)
+	require.NoError(t, hub.Publish(Event{Type: EventSubscription, UserID: 1}))
+	assert.Equal(t, EventSubscription, receive(t, events).Type)
+
+	unsubscribe()
+	unsubscribe()
+	require.NoError(t, hub.Publish(Event{Type: EventSubscription, UserID: 1}))
+	assert.Empty(t, events)
+	assert.Empty(t, hub.clients)
+}
+
+func TestHubClose(t *testing.T) {
+	hub := NewHub(nil)
+	events, unsubscribe := hub.Subscribe(1)
+
+	hub.Close()
+	_, open := <-events
+	assert.False(t, open)
+	unsubscribe()
+
+	late, _ := hub.Subscribe(2)
+	_, open = <-late
+	assert.False(t, open)
+}
+
+func TestHubDropsEventsForSlowClient(t *testing.T) {
+	hub := NewHub(nil)
+	events, unsubscribe := hub.Subscribe(1)
+	defer unsubscribe()
+
+	for i := 0; i < clientBuffer+5; i++ {
+		require.NoError(t, hub.Publish(Event{Type: EventNotification, UserID: 1}))
+	}
+	assert.Len(t, events, clientBuffer)
+}
@@ -0,0 +1,49 @@
+package stream
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"log"
+)
+
+// PGBroker передаёт события через Postgres LISTEN/NOTIFY.
+type PGBroker struct {
+	pool *pgxpool.Pool
+}
+
+func NewPGBroker(pool *pgxpool.Pool) *PGBroker {
+	return &PGBroker{pool: pool}
+}
+
+func (b *PGBroker) Publish(ctx context.Context, channel, payload string) error {
+	_, err := b.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, channel, payload)
+	return err
+}
+
+// Listen держит отдельное соединение пула на всё время прослушивания.
+func (b *PGBroker) Listen(ctx context.Context, channel string, handle func(payload string)) error {
+	conn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// закрытое соединение пул не переиспользует, так подписка LISTEN не утечёт к другим запросам
+		if err := conn.Conn().Close(context.Background()); err != nil {
+			log.Printf("Error closing listen connection: %v", err)
+		}
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handle(notification.Payload)
+	}
+}
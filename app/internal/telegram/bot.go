@@ -0,0 +1,81 @@
+package telegram
+
+import (
+	telegramRepo "birthdayReminder/internal/repository/telegram"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+const pollTimeout = 30 * time.Second
+
+// Bot принимает сообщения боту и привязывает чаты по одноразовым кодам.
+// Пользователь отправляет боту код или открывает ссылку https://t.me/<bot>?start=<code>.
+type Bot struct {
+	client *Client
+	linker Linker
+}
+
+func NewBot(client *Client, linker Linker) *Bot {
+	return &Bot{client: client, linker: linker}
+}
+
+// Run опрашивает Bot API, пока не будет отменён ctx.
+func (b *Bot) Run(ctx context.Context) {
+	log.Println("Telegram bot started")
+
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := b.client.GetUpdates(ctx, offset, pollTimeout)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			log.Println("Error fetching telegram updates:", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message != nil {
+				b.handleMessage(*update.Message)
+			}
+		}
+	}
+
+	log.Println("Telegram bot stopped")
+}
+
+func (b *Bot) handleMessage(msg IncomingMessage) {
+	code := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(msg.Text), "/start"))
+	if code == "" {
+		b.reply(msg.Chat.ID, "Отправьте код привязки из приложения, чтобы получать напоминания о днях рождения.")
+		return
+	}
+
+	userID, err := b.linker.ConsumeLinkCode(strings.ToUpper(code), msg.Chat.ID)
+	if errors.Is(err, telegramRepo.ErrInvalidCode) {
+		b.reply(msg.Chat.ID, "Код не найден или устарел. Запросите новый код в приложении.")
+		return
+	}
+	if err != nil {
+		log.Println("Error linking telegram chat:", err)
+		b.reply(msg.Chat.ID, "Не удалось привязать чат, попробуйте позже.")
+		return
+	}
+
+	log.Printf("Linked telegram chat to user ID %d\n", userID)
+	b.reply(msg.Chat.ID, "Готово! Теперь напоминания о днях рождения будут приходить сюда.")
+}
+
+func (b *Bot) reply(chatID int64, text string) {
+	if err := b.client.SendMessage(chatID, text); err != nil {
+		log.Println("Error replying in telegram:", err)
+	}
+}
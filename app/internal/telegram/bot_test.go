@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	telegramRepo "birthdayReminder/internal/repository/telegram"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeLinker struct {
+	codes  map[string]int
+	err    error
+	linked map[int64]int
+}
+
+func (l *fakeLinker) ConsumeLinkCode(code string, chatID int64) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	userID, ok := l.codes[code]
+	if !ok {
+		return 0, telegramRepo.ErrInvalidCode
+	}
+	delete(l.codes, code)
+	l.linked[chatID] = userID
+	return userID, nil
+}
+
+func TestBotHandleMessage(t *testing.T) {
+	testCases := []struct {
+		name          string
+		text          string
+		linkerErr     error
+		expectedLinks map[int64]int
+		expectedReply string
+	}{
+		{
+			name:          "Start command with code",
+			text:          "/start ABCD2345",
+			expectedLinks: map[int64]int{42: 7},
+			expectedReply: "Готово! Теперь напоминания о днях рождения будут приходить сюда.",
+		},
+		{
+			name:          "Code sent as plain text",
+			text:          " abcd2345 ",
+			expectedLinks: map[int64]int{42: 7},
+			expectedReply: "Готово! Теперь напоминания о днях рождения будут приходить сюда.",
+		},
+		{
+			name:          "Start without code",
+			text:          "/start",
+			expectedLinks: map[int64]int{},
+			expectedReply: "Отправьте код привязки из приложения, чтобы получать напоминания о днях рождения.",
+		},
+		{
+			name:          "Unknown code",
+			text:          "WRONG",
+			expectedLinks: map[int64]int{},
+			expectedReply: "Код не найден или устарел. Запросите новый код в приложении.",
+		},
+		{
+			name:          "Storage error",
+			text:          "ABCD2345",
+			linkerErr:     errors.New("db error"),
+			expectedLinks: map[int64]int{},
+			expectedReply: "Не удалось привязать чат, попробуйте позже.",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newBotAPIStub(t, nil)
+			linker := &fakeLinker{codes: map[string]int{"ABCD2345": 7}, err: tt.linkerErr, linked: map[int64]int{}}
+			bot := NewBot(NewClient(stub.server.URL, "TOKEN"), linker)
+
+			bot.handleMessage(IncomingMessage{Chat: Chat{ID: 42}, Text: tt.text})
+
+			assert.Equal(t, tt.expectedLinks, linker.linked)
+			if assert.Len(t, stub.requests, 1) {
+				assert.Equal(t, tt.expectedReply, stub.requests[0].params["text"])
+			}
+		})
+	}
+}
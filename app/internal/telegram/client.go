@@ -0,0 +1,121 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const DefaultBaseURL = "https://api.telegram.org"
+
+// Client - минимальный клиент Telegram Bot API.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient создаёт клиент; baseURL позволяет подменить api.telegram.org, например, тестовым сервером.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		// таймаут больше, чем long polling в GetUpdates
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+type IncomingMessage struct {
+	MessageID int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+type Update struct {
+	UpdateID int64            `json:"update_id"`
+	Message  *IncomingMessage `json:"message"`
+}
+
+// APIError - ошибка, которую вернул Bot API (ok=false).
+type APIError struct {
+	Code        int    `json:"error_code"`
+	Description string `json:"description"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram api error %d: %s", e.Code, e.Description)
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/bot"+c.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// в тексте ошибки URL содержит токен бота
+		return fmt.Errorf("telegram %s request failed: %v", method, strings.ReplaceAll(err.Error(), c.token, "***"))
+	}
+	defer resp.Body.Close()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("telegram %s: invalid response (status %d): %v", method, resp.StatusCode, err)
+	}
+	if !apiResp.OK {
+		return &APIError{Code: apiResp.ErrorCode, Description: apiResp.Description}
+	}
+	if result != nil {
+		return json.Unmarshal(apiResp.Result, result)
+	}
+	return nil
+}
+
+// SendMessage отправляет текстовое сообщение в чат.
+func (c *Client) SendMessage(chatID int64, text string) error {
+	params := map[string]interface{}{
+		"chat_id": chatID,
+		"text":    text,
+	}
+	return c.call(context.Background(), "sendMessage", params, nil)
+}
+
+// GetUpdates получает новые сообщения боту, ожидая их до timeout (long polling).
+// Отмена ctx прерывает ожидание.
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	params := map[string]interface{}{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}
+	var updates []Update
+	if err := c.call(ctx, "getUpdates", params, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
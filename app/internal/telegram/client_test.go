@@ -0,0 +1,91 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// botAPIStub имитирует Bot API: запоминает запросы и отвечает заданными ответами по имени метода.
+type botAPIStub struct {
+	server    *httptest.Server
+	requests  []stubRequest
+	responses map[string]string
+}
+
+type stubRequest struct {
+	path   string
+	params map[string]interface{}
+}
+
+func newBotAPIStub(t *testing.T, responses map[string]string) *botAPIStub {
+	stub := &botAPIStub{responses: responses}
+	stub.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+		stub.requests = append(stub.requests, stubRequest{path: r.URL.Path, params: params})
+
+		response, ok := stub.responses[r.URL.Path]
+		if !ok {
+			response = `{"ok":true,"result":true}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+func TestClientSendMessage(t *testing.T) {
+	stub := newBotAPIStub(t, map[string]string{
+		"/botTOKEN/sendMessage": `{"ok":true,"result":{"message_id":1}}`,
+	})
+
+	err := NewClient(stub.server.URL+"/", "TOKEN").SendMessage(42, "Привет")
+	require.NoError(t, err)
+
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, "/botTOKEN/sendMessage", stub.requests[0].path)
+	assert.Equal(t, float64(42), stub.requests[0].params["chat_id"])
+	assert.Equal(t, "Привет", stub.requests[0].params["text"])
+}
+
+func TestClientAPIError(t *testing.T) {
+	stub := newBotAPIStub(t, map[string]string{
+		"/botTOKEN/sendMessage": `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`,
+	})
+
+	err := NewClient(stub.server.URL, "TOKEN").SendMessage(42, "Привет")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 403, apiErr.Code)
+	assert.Equal(t, "telegram api error 403: Forbidden: bot was blocked by the user", err.Error())
+}
+
+func TestClientGetUpdates(t *testing.T) {
+	stub := newBotAPIStub(t, map[string]string{
+		"/botTOKEN/getUpdates": `{"ok":true,"result":[{"update_id":7,"message":{"message_id":3,"chat":{"id":42},"text":"/start ABCD2345"}}]}`,
+	})
+
+	updates, err := NewClient(stub.server.URL, "TOKEN").GetUpdates(context.Background(), 5, 30*time.Second)
+	require.NoError(t, err)
+
+	require.Len(t, updates, 1)
+	assert.Equal(t, int64(7), updates[0].UpdateID)
+	assert.Equal(t, int64(42), updates[0].Message.Chat.ID)
+	assert.Equal(t, "/start ABCD2345", updates[0].Message.Text)
+	assert.Equal(t, float64(5), stub.requests[0].params["offset"])
+	assert.Equal(t, float64(30), stub.requests[0].params["timeout"])
+}
+
+func TestClientHidesTokenInErrors(t *testing.T) {
+	err := NewClient("http://127.0.0.1:1", "SECRET").SendMessage(42, "Привет")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "SECRET")
+}
@@ -0,0 +1,6 @@
+package telegram
+
+// Linker погашает код привязки и сохраняет чат за владельцем кода.
+type Linker interface {
+	ConsumeLinkCode(code string, chatID int64) (int, error)
+}
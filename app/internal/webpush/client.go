@@ -0,0 +1,89 @@
+package webpush
+
+import (
+	"birthdayReminder/internal/netguard"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSubscriptionGone - push-сервис сообщил, что подписка больше не действует (404 или 410),
+// её нужно удалить.
+var ErrSubscriptionGone = errors.New("push subscription expired")
+
+// Options - заголовки доставки из RFC 8030.
+type Options struct {
+	// TTL - сколько push-сервис хранит сообщение, если устройство не в сети.
+	TTL time.Duration
+	// Topic заменяет ещё не доставленное сообщение с тем же топиком; до 32 символов base64url.
+	Topic string
+}
+
+// Client отправляет зашифрованные сообщения в push-сервисы браузеров.
+type Client struct {
+	vapid        *VAPID
+	httpClient   *http.Client
+	allowPrivate bool
+}
+
+// NewClient создает клиент, который отправляет только на https и не ходит во внутреннюю сеть.
+// allowPrivate разрешает http и внутренние адреса для тестов с локальной заглушкой push-сервиса.
+func NewClient(vapid *VAPID, allowPrivate bool) *Client {
+	return &Client{vapid: vapid, httpClient: netguard.NewHTTPClient(10*time.Second, allowPrivate), allowPrivate: allowPrivate}
+}
+
+// CheckEndpoint проверяет адрес push-сервиса: https и не внутренняя сеть; allowPrivate разрешает и http-заглушку.
+func CheckEndpoint(endpoint string, allowPrivate bool) error {
+	if allowPrivate {
+		return netguard.CheckURL(endpoint, true, "https", "http")
+	}
+	return netguard.CheckURL(endpoint, false, "https")
+}
+
+func (c *Client) Send(sub Subscription, payload []byte, opts Options) error {
+	if err := CheckEndpoint(sub.Endpoint, c.allowPrivate); err != nil {
+		return fmt.Errorf("invalid push endpoint: %w", err)
+	}
+
+	body, err := Encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+
+	authorization, err := c.vapid.Authorization(sub.Endpoint, time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(opts.TTL.Seconds())))
+	if opts.Topic != "" {
+		req.Header.Set("Topic", opts.Topic)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		reply, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push service responded %d: %s", resp.StatusCode, strings.TrimSpace(string(reply)))
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pushServiceStub - локальный push-сервис: проверяет VAPID, расшифровывает сообщение
+// ключами «браузера» и отвечает кодом status.
+type pushServiceStub struct {
+	t          *testing.T
+	vapid      *VAPID
+	uaPrivate  *ecdh.PrivateKey
+	authSecret []byte
+	status     int
+	received   []string
+	headers    []http.Header
+}
+
+func (s *pushServiceStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	require.True(s.t, strings.HasPrefix(auth, "vapid t="))
+	parts := strings.SplitN(strings.TrimPrefix(auth, "vapid t="), ", k=", 2)
+	require.Len(s.t, parts, 2)
+	assert.Equal(s.t, s.vapid.PublicKey, parts[1])
+
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(parts[0], claims, func(token *jwt.Token) (interface{}, error) {
+		return &s.vapid.privateKey.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}), jwt.WithAudience("http://"+r.Host))
+	require.NoError(s.t, err)
+	assert.Equal(s.t, "mailto:admin@example.com", claims.Subject)
+
+	body, _ := io.ReadAll(r.Body)
+	s.received = append(s.received, string(decrypt(s.t, s.uaPrivate, s.authSecret, body)))
+	s.headers = append(s.headers, r.Header.Clone())
+	w.WriteHeader(s.status)
+}
+
+func newStubSubscription(t *testing.T, status int) (*pushServiceStub, *httptest.Server, Subscription) {
+	public, private, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+	vapid, err := NewVAPID(public, private, "mailto:admin@example.com")
+	require.NoError(t, err)
+
+	uaPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	authSecret := make([]byte, authSize)
+	_, err = rand.Read(authSecret)
+	require.NoError(t, err)
+
+	stub := &pushServiceStub{t: t, vapid: vapid, uaPrivate: uaPrivate, authSecret: authSecret, status: status}
+	server := httptest.NewServer(stub)
+	sub := Subscription{
+		Endpoint: server.URL + "/push/abc",
+		P256dh:   base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(authSecret),
+	}
+	return stub, server, sub
+}
+
+func TestClientSend(t *testing.T) {
+	stub, server, sub := newStubSubscription(t, http.StatusCreated)
+	defer server.Close()
+
+	client := NewClient(stub.vapid, true)
+	err := client.Send(sub, []byte(`{"title":"Напоминание"}`), Options{TTL: 24 * time.Hour, Topic: "reminder"})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{`{"title":"Напоминание"}`}, stub.received)
+	assert.Equal(t, "aes128gcm", stub.headers[0].Get("Content-Encoding"))
+	assert.Equal(t, "86400", stub.headers[0].Get("TTL"))
+	assert.Equal(t, "reminder", stub.headers[0].Get("Topic"))
+}
+
+func TestClientRefusesInsecureEndpoints(t *testing.T) {
+	stub, server, sub := newStubSubscription(t, http.StatusCreated)
+	defer server.Close()
+
+	// заглушка работает по http на 127.0.0.1: без allowPrivate запрос не отправляется
+	err := NewClient(stub.vapid, false).Send(sub, []byte("{}"), Options{TTL: time.Hour})
+	require.Error(t, err)
+	assert.Empty(t, stub.received)
+
+	assert.NoError(t, CheckEndpoint("https://fcm.googleapis.com/fcm/send/abc", false))
+	assert.Error(t, CheckEndpoint("http://fcm.googleapis.com/fcm/send/abc", false))
+	assert.Error(t, CheckEndpoint("https://10.0.0.1/push", false))
+	assert.NoError(t, CheckEndpoint(sub.Endpoint, true))
+}
+
+func TestClientSendErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		expectedErr error
+	}{
+		{name: "Expired subscription", status: http.StatusGone, expectedErr: ErrSubscriptionGone},
+		{name: "Unknown subscription", status: http.StatusNotFound, expectedErr: ErrSubscriptionGone},
+		{name: "Push service failure", status: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stub, server, sub := newStubSubscription(t, tt.status)
+			defer server.Close()
+
+			err := NewClient(stub.vapid, true).Send(sub, []byte("{}"), Options{TTL: time.Hour})
+			require.Error(t, err)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NotErrorIs(t, err, ErrSubscriptionGone)
+			}
+		})
+	}
+}
+
+func TestNewVAPID(t *testing.T) {
+	public, private, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+	otherPublic, _, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+
+	_, err = NewVAPID(public, private, "mailto:admin@example.com")
+	assert.NoError(t, err)
+	_, err = NewVAPID(otherPublic, private, "mailto:admin@example.com")
+	assert.Error(t, err)
+	_, err = NewVAPID(public, "not-a-key", "mailto:admin@example.com")
+	assert.Error(t, err)
+	_, err = NewVAPID(public, private, "")
+	assert.Error(t, err)
+}
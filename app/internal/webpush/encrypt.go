@@ -0,0 +1,142 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/hkdf"
+	"io"
+)
+
+const (
+	// recordSize - размер записи aes128gcm; payload шифруется одной записью.
+	recordSize = 4096
+	saltSize   = 16
+	authSize   = 16
+	keySize    = 65
+	tagSize    = 16
+	headerSize = saltSize + 4 + 1 + keySize
+	// MaxPayloadSize - наибольший открытый текст, который помещается в одну запись (RFC 8291, раздел 4).
+	MaxPayloadSize = recordSize - headerSize - tagSize - 1
+)
+
+var ErrPayloadTooLarge = errors.New("push payload is too large")
+
+// Subscription - подписка браузера из PushSubscription.toJSON(); ключи в base64url.
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// decodeKey принимает base64url с выравниванием и без: браузеры отдают ключи по-разному.
+func decodeKey(s string) ([]byte, error) {
+	if b, err := base64.RawURLEncoding.DecodeString(s); err == nil {
+		return b, nil
+	}
+	return base64.URLEncoding.DecodeString(s)
+}
+
+func subscriptionKeys(p256dh, auth string) (*ecdh.PublicKey, []byte, error) {
+	rawKey, err := decodeKey(p256dh)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(rawKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+
+	authSecret, err := decodeKey(auth)
+	if err != nil || len(authSecret) != authSize {
+		return nil, nil, errors.New("invalid auth secret")
+	}
+	return uaPublic, authSecret, nil
+}
+
+// ValidateKeys проверяет ключи подписки, присланные браузером.
+func ValidateKeys(p256dh, auth string) error {
+	_, _, err := subscriptionKeys(p256dh, auth)
+	return err
+}
+
+// Encrypt шифрует payload для подписки по RFC 8291 (кодирование aes128gcm из RFC 8188).
+func Encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encrypt(sub, payload, asPrivate, salt)
+}
+
+func encrypt(sub Subscription, payload []byte, asPrivate *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	uaPublic, authSecret, err := subscriptionKeys(sub.P256dh, sub.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdhSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	// key_info = "WebPush: info" || 0x00 || ua_public || as_public
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic.Bytes()...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm, err := expand(hkdf.Extract(sha256.New, ecdhSecret, authSecret), keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek, err := expand(prk, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := expand(prk, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, headerSize)
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	// единственная запись завершается разделителем 0x02
+	plaintext := append(append(make([]byte, 0, len(payload)+1), payload...), 0x02)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+func expand(prk, info []byte, length int) ([]byte, error) {
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/hkdf"
+	"testing"
+)
+
+func b64(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// decrypt - обратная операция на стороне браузера, для проверки сообщений в тестах.
+func decrypt(t *testing.T, uaPrivate *ecdh.PrivateKey, authSecret, body []byte) []byte {
+	t.Helper()
+	require.Greater(t, len(body), headerSize)
+
+	salt := body[:saltSize]
+	assert.Equal(t, uint32(recordSize), binary.BigEndian.Uint32(body[saltSize:saltSize+4]))
+	require.Equal(t, byte(keySize), body[saltSize+4])
+	asPublic, err := ecdh.P256().NewPublicKey(body[saltSize+5 : headerSize])
+	require.NoError(t, err)
+
+	ecdhSecret, err := uaPrivate.ECDH(asPublic)
+	require.NoError(t, err)
+	keyInfo := append([]byte("WebPush: info\x00"), uaPrivate.PublicKey().Bytes()...)
+	keyInfo = append(keyInfo, asPublic.Bytes()...)
+	ikm, err := expand(hkdf.Extract(sha256.New, ecdhSecret, authSecret), keyInfo, 32)
+	require.NoError(t, err)
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek, err := expand(prk, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	require.NoError(t, err)
+	nonce, err := expand(prk, []byte("Content-Encoding: nonce\x00"), 12)
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(cek)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	plaintext, err := gcm.Open(nil, nonce, body[headerSize:], nil)
+	require.NoError(t, err)
+	require.Equal(t, byte(0x02), plaintext[len(plaintext)-1])
+	return plaintext[:len(plaintext)-1]
+}
+
+// Пример из приложения A RFC 8291.
+func TestEncryptRFC8291Example(t *testing.T) {
+	asPrivate, err := ecdh.P256().NewPrivateKey(b64(t, "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+	require.NoError(t, err)
+	sub := Subscription{
+		Endpoint: "https://push.example.net/push/JzLQ3raZJfFBR0aqvOMsLrt54w4rJUsV",
+		P256dh:   "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+		Auth:     "BTBZMqHH6r4Tts7J_aSIgg",
+	}
+
+	body, err := encrypt(sub, []byte("When I grow up, I want to be a watermelon"), asPrivate, b64(t, "DGv6ra1nlYgDCS1FRnbzlw"))
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		"DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN",
+		base64.RawURLEncoding.EncodeToString(body),
+	)
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	uaPrivate, err := ecdh.P256().NewPrivateKey(b64(t, "q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94"))
+	require.NoError(t, err)
+	authSecret := b64(t, "BTBZMqHH6r4Tts7J_aSIgg")
+	sub := Subscription{
+		P256dh: base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes()),
+		Auth:   base64.URLEncoding.EncodeToString(authSecret),
+	}
+
+	first, err := Encrypt(sub, []byte(`{"title":"Напоминание"}`))
+	require.NoError(t, err)
+	second, err := Encrypt(sub, []byte(`{"title":"Напоминание"}`))
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second, "salt and ephemeral key must be fresh for every message")
+
+	assert.Equal(t, `{"title":"Напоминание"}`, string(decrypt(t, uaPrivate, authSecret, first)))
+
+	_, err = Encrypt(sub, make([]byte, MaxPayloadSize+1))
+	assert.ErrorIs(t, err, ErrPayloadTooLarge)
+}
+
+func TestValidateKeys(t *testing.T) {
+	assert.NoError(t, ValidateKeys("BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4", "BTBZMqHH6r4Tts7J_aSIgg"))
+	assert.Error(t, ValidateKeys("BCVxsr7N", "BTBZMqHH6r4Tts7J_aSIgg"))
+	assert.Error(t, ValidateKeys("BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4", "c2hvcnQ"))
+}
@@ -0,0 +1,94 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"net/url"
+	"time"
+)
+
+// vapidTokenTTL - срок жизни токена VAPID; RFC 8292 допускает не больше 24 часов.
+const vapidTokenTTL = 12 * time.Hour
+
+// VAPID идентифицирует сервер приложения перед push-сервисом (RFC 8292).
+type VAPID struct {
+	// PublicKey - несжатый открытый ключ P-256 в base64url, его же браузер получает для pushManager.subscribe.
+	PublicKey  string
+	Subject    string
+	privateKey *ecdsa.PrivateKey
+}
+
+// NewVAPID загружает пару ключей в base64url: открытый - 65 байт несжатой точки, закрытый - 32 байта скаляра.
+// subject - контакт администратора, mailto: или https: адрес.
+func NewVAPID(publicKey, privateKey, subject string) (*VAPID, error) {
+	rawPrivate, err := decodeKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(rawPrivate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+
+	rawPublic, err := decodeKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID public key: %w", err)
+	}
+	if !bytes.Equal(rawPublic, key.PublicKey().Bytes()) {
+		return nil, errors.New("VAPID public key does not match private key")
+	}
+
+	if subject == "" {
+		return nil, errors.New("VAPID subject is required")
+	}
+
+	return &VAPID{
+		PublicKey: base64.RawURLEncoding.EncodeToString(rawPublic),
+		Subject:   subject,
+		privateKey: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(rawPublic[1:33]),
+				Y:     new(big.Int).SetBytes(rawPublic[33:]),
+			},
+			D: new(big.Int).SetBytes(rawPrivate),
+		},
+	}, nil
+}
+
+// GenerateVAPIDKeys создаёт новую пару ключей в формате NewVAPID.
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(key.Bytes()), nil
+}
+
+// Authorization возвращает значение заголовка Authorization для запроса к endpoint.
+func (v *VAPID) Authorization(endpoint string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{u.Scheme + "://" + u.Host},
+		ExpiresAt: jwt.NewNumericDate(now.Add(vapidTokenTTL)),
+		Subject:   v.Subject,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(v.privateKey)
+	if err != nil {
+		return "", err
+	}
+	return "vapid t=" + token + ", k=" + v.PublicKey, nil
+}